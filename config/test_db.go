@@ -3,27 +3,70 @@ package config
 import (
 	"log"
 	"software_management/models"
+	"strings"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
 )
 
 var TestDB *gorm.DB
 
+// testDialector is SQLite with MySQL ENUM columns created as plain text, which SQLite cannot parse
+type testDialector struct {
+	*sqlite.Dialector
+}
+
+func (d testDialector) DataTypeOf(field *schema.Field) string {
+	if strings.HasPrefix(strings.ToLower(string(field.DataType)), "enum(") {
+		return "text"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+func (d testDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return sqlite.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// testModels are the models migrated into the test DB, in an order that satisfies their foreign keys
+var testModels = []interface{}{
+	&models.Department{}, &models.Team{}, &models.Staff{}, &models.Software{},
+	&models.AssignedSoftware{}, &models.AssignmentGrant{}, &models.SoftwareAssignmentLog{}, &models.SoftwareAssignment{},
+	&models.SoftwareDepartmentMatch{}, &models.SoftwareTeamMatch{}, &models.SoftwareOrganizationMatch{},
+	&models.Vendor{}, &models.Contract{}, &models.Reminder{},
+	&models.LicenseKey{}, &models.LicenseKeyAccessLog{}, &models.SoftwarePlan{},
+	&models.UsageEvent{},
+	&models.ReclamationPolicy{}, &models.SoftwareAttributeRule{}, &models.SoftwareExclusion{},
+	&models.SoftwareBundle{}, &models.SoftwareBundleItem{}, &models.BundleAssignment{}, &models.SoftwareBundleMatch{}, &models.SoftwarePolicy{}, &models.AccessRequest{},
+	&models.AccessReviewCampaign{}, &models.AccessReviewItem{},
+	&models.Onboarding{}, &models.OnboardingItem{},
+	&models.Offboarding{}, &models.OffboardingItem{},
+}
+
+// InitTestDB opens an in-memory SQLite database, migrates all models and makes it config.DB
 func InitTestDB() {
 	var err error
-	TestDB, err = gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	TestDB, err = gorm.Open(testDialector{sqlite.Open("file::memory:?cache=shared").(*sqlite.Dialector)}, &gorm.Config{})
 	if err != nil {
 		log.Fatalf("Failed to connect to test DB: %v", err)
 	}
 
 	// Migrate all models
-	err = TestDB.AutoMigrate(
-		&models.Department{}, &models.Team{}, &models.Staff{}, &models.Software{},
-		&models.AssignedSoftware{}, &models.SoftwareAssignmentLog{},
-		&models.SoftwareDepartmentMatch{}, &models.SoftwareTeamMatch{}, &models.SoftwareOrganizationMatch{},
-	)
+	err = TestDB.AutoMigrate(testModels...)
 	if err != nil {
 		log.Fatalf("Failed to migrate test DB: %v", err)
 	}
+	DB = TestDB
+}
+
+// ResetTestDB empties every table of the test DB
+func ResetTestDB() {
+	for _, model := range testModels {
+		TestDB.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(model)
+	}
 }
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAccessRequests godoc
+// @Summary List software access requests
+// @Description Requests with their requester, software, decision and approver, newest first, for review and audit
+// @Tags Access Requests
+// @Produce json
+// @Param status query string false "Filter by status (pending/approved/rejected/cancelled)"
+// @Param staff_id query int false "Filter by requester Staff ID"
+// @Param software_id query int false "Filter by Software ID"
+// @Param approver_id query int false "Filter by approver Staff ID"
+// @Param start_date query string false "Submitted on or after (YYYY-MM-DD)"
+// @Param end_date query string false "Submitted on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {array} models.AccessRequest
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-requests [get]
+func GetAccessRequests(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	offset := (page - 1) * pageSize
+
+	query := config.DB.Preload("Staff").Preload("Software").Preload("Approver")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", strings.ToLower(status))
+	}
+	if staffID := c.Query("staff_id"); staffID != "" {
+		query = query.Where("staff_id = ?", staffID)
+	}
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ?", sw)
+	}
+	if approverID := c.Query("approver_id"); approverID != "" {
+		query = query.Where("approver_id = ?", approverID)
+	}
+	if startDate := c.Query("start_date"); startDate != "" {
+		query = query.Where("DATE(created_at) >= ?", startDate)
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		query = query.Where("DATE(created_at) <= ?", endDate)
+	}
+
+	var requests []models.AccessRequest
+	if err := query.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetAccessRequestByID godoc
+// @Summary Get a software access request
+// @Tags Access Requests
+// @Produce json
+// @Param id path int true "Access Request ID"
+// @Success 200 {object} models.AccessRequest
+// @Failure 404 {object} models.APIResponse
+// @Router /api/access-requests/{id} [get]
+func GetAccessRequestByID(c *gin.Context) {
+	var request models.AccessRequest
+	if err := config.DB.Preload("Staff").Preload("Software").Preload("Approver").
+		First(&request, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access request not found"})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// SubmitAccessRequest godoc
+// @Summary Request access to a software
+// @Description A staff member asks for a software with a justification. The request stays pending until it is approved, rejected or cancelled.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param request body models.AccessRequest true "Requester, software, optional plan and justification"
+// @Success 201 {object} models.AccessRequest
+// @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-requests [post]
+func SubmitAccessRequest(c *gin.Context) {
+	var input models.AccessRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	request := models.AccessRequest{
+		StaffID:       input.StaffID,
+		SoftwareID:    input.SoftwareID,
+		PlanID:        input.PlanID,
+		Justification: strings.TrimSpace(input.Justification),
+		Status:        utils.RequestPending,
+	}
+	if msg := validateAccessRequest(request); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	var held int64
+	config.DB.Model(&models.AssignedSoftware{}).
+		Where("staff_id = ? AND software_id = ?", request.StaffID, request.SoftwareID).Count(&held)
+	if held > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Staff member already has this software"})
+		return
+	}
+	var existing models.AccessRequest
+	if err := config.DB.Where("staff_id = ? AND software_id = ? AND status = ?", request.StaffID, request.SoftwareID, utils.RequestPending).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Staff member already has a pending request for this software"})
+		return
+	}
+
+	if err := config.DB.Create(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// ApproveAccessRequest godoc
+// @Summary Approve a software access request
+// @Description Assigns the software to the requester and logs the assignment under the approver, atomically. When the assignment is refused, e.g. for lack of seats, the request stays pending.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Access Request ID"
+// @Param decision body models.AccessRequestDecision true "Approver and optional note"
+// @Success 200 {object} models.AccessRequest
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-requests/{id}/approve [post]
+func ApproveAccessRequest(c *gin.Context) {
+	request, decision, ok := bindAccessRequestDecision(c)
+	if !ok {
+		return
+	}
+
+	if err := utils.ApproveAccessRequest(&request, decision.ApproverID, decision.Note); err != nil {
+		if errors.Is(err, utils.ErrPlanMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, utils.ErrRequestNotPending) || errors.Is(err, utils.ErrNoSeatsAvailable) ||
+			errors.Is(err, utils.ErrNoLicenseKeyAvailable) || errors.Is(err, utils.ErrOverBudget) ||
+			errors.Is(err, utils.ErrPolicyViolation) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// RejectAccessRequest godoc
+// @Summary Reject a software access request
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Access Request ID"
+// @Param decision body models.AccessRequestDecision true "Approver and optional note"
+// @Success 200 {object} models.AccessRequest
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-requests/{id}/reject [post]
+func RejectAccessRequest(c *gin.Context) {
+	request, decision, ok := bindAccessRequestDecision(c)
+	if !ok {
+		return
+	}
+
+	if err := utils.RejectAccessRequest(&request, decision.ApproverID, decision.Note); err != nil {
+		if errors.Is(err, utils.ErrRequestNotPending) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// CancelAccessRequest godoc
+// @Summary Cancel a pending software access request
+// @Tags Access Requests
+// @Produce json
+// @Param id path int true "Access Request ID"
+// @Success 200 {object} models.AccessRequest
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-requests/{id}/cancel [post]
+func CancelAccessRequest(c *gin.Context) {
+	var request models.AccessRequest
+	if err := config.DB.First(&request, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access request not found"})
+		return
+	}
+
+	if err := utils.CancelAccessRequest(&request); err != nil {
+		if errors.Is(err, utils.ErrRequestNotPending) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// bindAccessRequestDecision loads the request and the approve or reject body, answering the call
+// itself when either is invalid
+func bindAccessRequestDecision(c *gin.Context) (models.AccessRequest, models.AccessRequestDecision, bool) {
+	var request models.AccessRequest
+	var decision models.AccessRequestDecision
+	if err := config.DB.First(&request, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access request not found"})
+		return request, decision, false
+	}
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return request, decision, false
+	}
+
+	var approver models.Staff
+	if err := config.DB.First(&approver, decision.ApproverID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Approver not found"})
+		return request, decision, false
+	}
+	if approver.ID == request.StaffID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Staff cannot decide on their own access request"})
+		return request, decision, false
+	}
+	return request, decision, true
+}
+
+// validateAccessRequest returns a message describing what is wrong with a new access request, if anything
+func validateAccessRequest(request models.AccessRequest) string {
+	var staff models.Staff
+	if err := config.DB.First(&staff, request.StaffID).Error; err != nil {
+		return "Staff not found"
+	}
+	if strings.EqualFold(staff.Status, "inactive") {
+		return "Offboarded staff cannot request software"
+	}
+	var software models.Software
+	if err := config.DB.First(&software, request.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	if err := utils.ValidatePlan(request.SoftwareID, request.PlanID); err != nil {
+		return err.Error()
+	}
+	if request.Justification == "" {
+		return "Justification is required"
+	}
+	return ""
+}
@@ -0,0 +1,309 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAccessReviews godoc
+// @Summary List access review campaigns
+// @Tags Access Reviews
+// @Produce json
+// @Param status query string false "Filter by status (open/completed)"
+// @Success 200 {array} models.AccessReviewCampaign
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-reviews [get]
+func GetAccessReviews(c *gin.Context) {
+	var campaigns []models.AccessReviewCampaign
+	query := config.DB.Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", strings.ToLower(status))
+	}
+	if err := query.Find(&campaigns).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, campaigns)
+}
+
+// GetAccessReviewByID godoc
+// @Summary Get an access review campaign
+// @Tags Access Reviews
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} models.AccessReviewCampaign
+// @Failure 404 {object} models.APIResponse
+// @Router /api/access-reviews/{id} [get]
+func GetAccessReviewByID(c *gin.Context) {
+	var campaign models.AccessReviewCampaign
+	if err := config.DB.First(&campaign, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// CreateAccessReview godoc
+// @Summary Open an access review campaign
+// @Description Snapshots the active assignments of the organization, a department or a software into review items, each routed to the staff member's manager or the software's owner, as the campaign's reviewer setting prefers
+// @Tags Access Reviews
+// @Accept json
+// @Produce json
+// @Param campaign body models.AccessReviewCampaign true "Name, scope, reviewer (manager/owner) and optional due date"
+// @Success 201 {object} models.AccessReviewCampaign
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-reviews [post]
+func CreateAccessReview(c *gin.Context) {
+	var campaign models.AccessReviewCampaign
+	if err := c.ShouldBindJSON(&campaign); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateAccessReview(&campaign); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := utils.OpenAccessReview(&campaign); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// CompleteAccessReview godoc
+// @Summary Complete an access review campaign
+// @Description Closes the campaign to further decisions; items still pending stay undecided in its report
+// @Tags Access Reviews
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} models.AccessReviewCampaign
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-reviews/{id}/complete [post]
+func CompleteAccessReview(c *gin.Context) {
+	var campaign models.AccessReviewCampaign
+	if err := config.DB.First(&campaign, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	}
+	if err := utils.CompleteAccessReview(&campaign); err != nil {
+		if errors.Is(err, utils.ErrCampaignCompleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// GetAccessReviewReport godoc
+// @Summary Get the completion report of an access review campaign
+// @Description Kept, revoked and pending items, overall and per reviewer, and the exclusions left standing by revokes
+// @Tags Access Reviews
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} models.AccessReviewReport
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-reviews/{id}/report [get]
+func GetAccessReviewReport(c *gin.Context) {
+	var campaign models.AccessReviewCampaign
+	if err := config.DB.First(&campaign, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	}
+	report, err := utils.AccessReviewSummary(campaign)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetAccessReviewItems godoc
+// @Summary List the review items of a campaign
+// @Tags Access Reviews
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Param reviewer_id query int false "Filter by reviewer Staff ID"
+// @Param decision query string false "Filter by decision (pending/keep/revoke)"
+// @Success 200 {array} models.AccessReviewItem
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-reviews/{id}/items [get]
+func GetAccessReviewItems(c *gin.Context) {
+	query := config.DB.Preload("Staff").Preload("Software").Where("campaign_id = ?", c.Param("id"))
+	if reviewerID := c.Query("reviewer_id"); reviewerID != "" {
+		query = query.Where("reviewer_id = ?", reviewerID)
+	}
+	switch decision := strings.ToLower(c.Query("decision")); decision {
+	case "":
+	case "pending":
+		query = query.Where("decision IS NULL OR decision = ''")
+	default:
+		query = query.Where("decision = ?", decision)
+	}
+
+	var items []models.AccessReviewItem
+	if err := query.Order("id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// DecideAccessReviewItem godoc
+// @Summary Keep or revoke the access under review
+// @Description Only the item's reviewer may decide; anyone but the staff member may decide an item without a reviewer. Revoking removes the assignment with an "Unassigned (Access Review)" log entry and, when a match or rule granted it, excludes the staff member from the software until the exclusion is removed; such exclusions are listed in the campaign report.
+// @Tags Access Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review Item ID"
+// @Param decision body models.AccessReviewDecision true "Reviewer, keep or revoke, and optional note"
+// @Success 200 {object} models.AccessReviewItem
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-review-items/{id}/decision [post]
+func DecideAccessReviewItem(c *gin.Context) {
+	var item models.AccessReviewItem
+	if err := config.DB.First(&item, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review item not found"})
+		return
+	}
+	var input models.AccessReviewDecision
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	decision := strings.ToLower(input.Decision)
+	if decision != utils.ReviewKeep && decision != utils.ReviewRevoke {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Decision must be keep or revoke"})
+		return
+	}
+	if msg := validateReviewer(item, input.ReviewerID); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if item.ReviewerID != nil && *item.ReviewerID != input.ReviewerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the assigned reviewer can decide on this item"})
+		return
+	}
+
+	if err := utils.DecideReviewItem(&item, input.ReviewerID, decision, input.Note); err != nil {
+		if errors.Is(err, utils.ErrCampaignCompleted) || errors.Is(err, utils.ErrItemDecided) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// ReassignAccessReviewItem godoc
+// @Summary Hand a pending review item to another reviewer
+// @Tags Access Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review Item ID"
+// @Param body body object true "Reviewer Staff ID, e.g. {\"reviewer_id\": 4}"
+// @Success 200 {object} models.AccessReviewItem
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/access-review-items/{id}/reviewer [put]
+func ReassignAccessReviewItem(c *gin.Context) {
+	var item models.AccessReviewItem
+	if err := config.DB.First(&item, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review item not found"})
+		return
+	}
+	var input struct {
+		ReviewerID uint `json:"reviewer_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if item.Decision != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": utils.ErrItemDecided.Error()})
+		return
+	}
+	if msg := validateReviewer(item, input.ReviewerID); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if err := config.DB.Model(&item).Update("reviewer_id", input.ReviewerID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// validateAccessReview normalises the scope and reviewer setting of a new campaign and returns a
+// message describing what is wrong with it, if anything
+func validateAccessReview(campaign *models.AccessReviewCampaign) string {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" {
+		return "Name is required"
+	}
+	scope, ok := utils.NormalizeReviewScope(campaign.ScopeType)
+	if !ok {
+		return "Scope type must be Organization, Department or Software"
+	}
+	campaign.ScopeType = scope
+
+	switch scope {
+	case utils.ScopeOrganization:
+		campaign.ScopeID = 0
+	case utils.ScopeDepartment:
+		var department models.Department
+		if err := config.DB.First(&department, campaign.ScopeID).Error; err != nil {
+			return "Department not found"
+		}
+	case utils.ScopeSoftware:
+		var software models.Software
+		if err := config.DB.First(&software, campaign.ScopeID).Error; err != nil {
+			return "Software not found"
+		}
+	}
+
+	campaign.Reviewer = strings.ToLower(campaign.Reviewer)
+	if campaign.Reviewer == "" {
+		campaign.Reviewer = utils.ReviewByManager
+	}
+	if campaign.Reviewer != utils.ReviewByManager && campaign.Reviewer != utils.ReviewByOwner {
+		return "Reviewer must be manager or owner"
+	}
+	return ""
+}
+
+// validateReviewer returns a message describing why a staff member cannot review an item, if anything
+func validateReviewer(item models.AccessReviewItem, reviewerID uint) string {
+	var reviewer models.Staff
+	if err := config.DB.First(&reviewer, reviewerID).Error; err != nil {
+		return "Reviewer not found"
+	}
+	if reviewer.ID == item.StaffID {
+		return "Staff cannot review their own access"
+	}
+	if strings.EqualFold(reviewer.Status, "inactive") {
+		return "Reviewer has been offboarded"
+	}
+	return ""
+}
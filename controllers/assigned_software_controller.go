@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"software_management/config"
 	"software_management/models"
+	"software_management/utils"
 	"strconv"
 	"time"
 
@@ -19,7 +21,7 @@ import (
 // @Router /api/assigned-software [get]
 func GetAssignedSoftware(c *gin.Context) {
 	var assigned []models.AssignedSoftware
-	if err := config.DB.Find(&assigned).Error; err != nil {
+	if err := config.DB.Preload("Grants").Find(&assigned).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -46,7 +48,7 @@ func GetAssignedSoftwareByID(c *gin.Context) {
 	}
 
 	var assigned models.AssignedSoftware
-	if err := config.DB.First(&assigned, id).Error; err != nil {
+	if err := config.DB.Preload("Grants").First(&assigned, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Assigned software not found"})
 		return
 	}
@@ -59,9 +61,12 @@ func GetAssignedSoftwareByID(c *gin.Context) {
 // @Tags Assigned Software
 // @Accept json
 // @Produce json
+// @Description Set expires_at for a time-bound assignment; it is revoked with an Expired log entry once that passes. Set starts_at for a future-dated assignment; it stays pending, without taking a seat, until then. An expiry is refused with 409 when the staff member already holds the software through a match or rule. Software conflicting with one the staff member holds, or requiring one they lack, is refused with 409.
 // @Param assignment body models.AssignedSoftware true "Software assignment payload"
+// @Success 200 {object} models.AssignedSoftware "Staff already held the software, a manual grant was added"
 // @Success 201 {object} models.AssignedSoftware
 // @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/assign-software [post]
 func CreateAssignedSoftware(c *gin.Context) {
@@ -70,7 +75,57 @@ func CreateAssignedSoftware(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := config.DB.Create(&record).Error; err != nil {
+	if msg := validateExpiry(record.ExpiresAt); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if record.StartsAt != nil && record.ExpiresAt != nil && !record.ExpiresAt.After(*record.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be after starts_at"})
+		return
+	}
+
+	// Software the staff member already holds through a match or rule gets a manual grant on top,
+	// so that it stays when the match or rule goes away
+	var existing models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ? AND software_id = ?", record.StaffID, record.SoftwareID).
+		First(&existing).Error; err == nil {
+		// expires_at applies to the whole assignment, so it can only time-bound access that nothing
+		// but manual assignments is behind
+		shared, err := utils.HasAutoGrant(existing)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if shared && record.ExpiresAt != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Staff already holds this software through a match or rule; it cannot be given an expiry"})
+			return
+		}
+		grant := models.AssignmentGrant{SourceType: utils.SourceManual, PlanID: existing.PlanID}
+		if err := utils.AddGrant(existing.ID, grant); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// The manual grant lasts as long as this request says
+		if err := config.DB.Model(&existing).Update("expires_at", record.ExpiresAt).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		config.DB.Preload("Grants").First(&existing, existing.ID)
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+
+	record.Grants = nil
+	if err := utils.CreateAssignment(&record); err != nil {
+		if errors.Is(err, utils.ErrPlanMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, utils.ErrNoSeatsAvailable) || errors.Is(err, utils.ErrNoLicenseKeyAvailable) ||
+			errors.Is(err, utils.ErrOverBudget) || errors.Is(err, utils.ErrPolicyViolation) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -79,6 +134,7 @@ func CreateAssignedSoftware(c *gin.Context) {
 
 // UpdateAssignedSoftware godoc
 // @Summary Update an assigned software record
+// @Description staff_id and software_id cannot be changed; assign the software anew instead. An expiry is refused with 409 when the staff member holds the software through a match or rule.
 // @Tags Assigned Software
 // @Accept json
 // @Produce json
@@ -87,6 +143,8 @@ func CreateAssignedSoftware(c *gin.Context) {
 // @Success 200 {object} models.AssignedSoftware
 // @Failure 400 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
 // @Router /api/assigned-software/{id} [put]
 func UpdateAssignedSoftware(c *gin.Context) {
 	var record models.AssignedSoftware
@@ -96,11 +154,48 @@ func UpdateAssignedSoftware(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
 		return
 	}
+	stored := record
+	var expiresAt time.Time
+	if stored.ExpiresAt != nil {
+		expiresAt = *stored.ExpiresAt
+	}
 	if err := c.ShouldBindJSON(&record); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	config.DB.Save(&record)
+	// Moving the seat would bypass the seat, policy and budget checks of a new assignment
+	if record.ID != stored.ID || record.StaffID != stored.StaffID || record.SoftwareID != stored.SoftwareID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "staff_id and software_id cannot be changed; assign the software anew instead"})
+		return
+	}
+	// Only a changed expiry has to lie in the future, and, as on creation, it can only time-bound
+	// access that nothing but manual assignments is behind
+	if record.ExpiresAt != nil && !record.ExpiresAt.Equal(expiresAt) {
+		if msg := validateExpiry(record.ExpiresAt); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		shared, err := utils.HasAutoGrant(stored)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if shared {
+			c.JSON(http.StatusConflict, gin.H{"error": "Staff holds this software through a match or rule; it cannot be given an expiry"})
+			return
+		}
+	}
+	if err := utils.ValidatePlan(record.SoftwareID, record.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Grants and activation are managed by the assignment engine, not through this endpoint
+	record.Grants = nil
+	record.Status = stored.Status
+	if err := config.DB.Save(&record).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, record)
 }
 
@@ -120,6 +215,12 @@ func DeleteAssignedSoftware(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	// Return any license key it held to the pool and drop its grants
+	if assignmentID, err := strconv.Atoi(id); err == nil {
+		utils.ReleaseLicenseKey(uint(assignmentID))
+		config.DB.Where("assigned_software_id = ?", assignmentID).Delete(&models.AssignmentGrant{})
+	}
 	c.Status(http.StatusNoContent)
 }
 
@@ -141,20 +242,43 @@ func DeleteAssignedSoftwareWithLogging(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&assignment).Error; err != nil {
+	// Delete, release its license key and log unassignment
+	if err := utils.RevokeAssignment(assignment, utils.ActionUnassigned); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete assignment"})
 		return
 	}
 
-	// Log unassignment
-	log := models.SoftwareAssignmentLog{
-		StaffID:    assignment.StaffID,
-		SoftwareID: assignment.SoftwareID,
-		Action:     "Unassigned",
-		ChangedBy:  0, // Set to current user ID if available
-		ChangedAt:  time.Now(),
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment removed"})
+}
+
+// GetExpiringAssignments godoc
+// @Summary List time-bound assignments expiring soon
+// @Tags Assigned Software
+// @Produce json
+// @Param within query string false "Look-ahead window, e.g. 14d, 2w (default 30d)"
+// @Success 200 {array} models.ExpiringAssignment
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/assigned-software/expiring [get]
+func GetExpiringAssignments(c *gin.Context) {
+	within, err := utils.ParseWithin(c.DefaultQuery("within", "30d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	config.DB.Create(&log)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Assignment removed"})
+	expiring, err := utils.FindExpiringAssignments(within)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, expiring)
+}
+
+// validateExpiry returns a message describing what is wrong with an assignment expiry, if anything
+func validateExpiry(expiresAt *time.Time) string {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "expires_at must be in the future"
+	}
+	return ""
 }
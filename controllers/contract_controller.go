@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetContracts godoc
+// @Summary List contracts
+// @Description Retrieves all contracts, optionally filtered by vendor_id or software_id
+// @Tags Contracts
+// @Produce json
+// @Param vendor_id query int false "Filter by Vendor ID"
+// @Param software_id query int false "Filter by Software ID"
+// @Success 200 {array} models.Contract
+// @Failure 500 {object} models.APIResponse
+// @Router /api/contracts [get]
+func GetContracts(c *gin.Context) {
+	var contracts []models.Contract
+	query := config.DB.Preload("Vendor").Preload("Software")
+
+	if vendor := c.Query("vendor_id"); vendor != "" {
+		query = query.Where("vendor_id = ?", vendor)
+	}
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ?", sw)
+	}
+
+	if err := query.Order("end_date ASC").Find(&contracts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, contracts)
+}
+
+// GetContractByID godoc
+// @Summary Get a contract by ID
+// @Tags Contracts
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} models.Contract
+// @Failure 404 {object} models.APIResponse
+// @Router /api/contracts/{id} [get]
+func GetContractByID(c *gin.Context) {
+	id := c.Param("id")
+	var contract models.Contract
+	if err := config.DB.Preload("Vendor").Preload("Software").First(&contract, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return
+	}
+	c.JSON(http.StatusOK, contract)
+}
+
+// CreateContract godoc
+// @Summary Create a new contract
+// @Tags Contracts
+// @Accept json
+// @Produce json
+// @Param contract body models.Contract true "Contract object"
+// @Success 201 {object} models.Contract
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/contracts [post]
+func CreateContract(c *gin.Context) {
+	var contract models.Contract
+	if err := c.ShouldBindJSON(&contract); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateContract(&contract); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	// Check for duplicate contract number
+	var existing models.Contract
+	if err := config.DB.Where("contract_number = ?", contract.ContractNumber).First(&existing).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A contract with this number already exists"})
+		return
+	}
+
+	if err := config.DB.Omit("Vendor", "Software").Create(&contract).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	config.DB.Preload("Vendor").Preload("Software").First(&contract, contract.ID)
+	c.JSON(http.StatusCreated, contract)
+}
+
+// UpdateContract godoc
+// @Summary Update a contract by ID
+// @Tags Contracts
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param contract body models.Contract true "Updated contract"
+// @Success 200 {object} models.Contract
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/contracts/{id} [put]
+func UpdateContract(c *gin.Context) {
+	id := c.Param("id")
+	var contract models.Contract
+	if err := config.DB.First(&contract, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return
+	}
+
+	var input models.Contract
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateContract(&input); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	// Check for duplicate contract number (excluding self)
+	var duplicate models.Contract
+	if err := config.DB.Where("contract_number = ? AND id != ?", input.ContractNumber, id).First(&duplicate).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Another contract with this number already exists"})
+		return
+	}
+
+	contract.VendorID = input.VendorID
+	contract.SoftwareID = input.SoftwareID
+	contract.ContractNumber = input.ContractNumber
+	contract.StartDate = input.StartDate
+	contract.EndDate = input.EndDate
+	contract.AutoRenew = input.AutoRenew
+	contract.NoticePeriodDays = input.NoticePeriodDays
+	contract.SeatCount = input.SeatCount
+	contract.Value = input.Value
+	contract.Currency = input.Currency
+	contract.Notes = input.Notes
+
+	if err := config.DB.Omit("Vendor", "Software").Save(&contract).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	config.DB.Preload("Vendor").Preload("Software").First(&contract, contract.ID)
+	c.JSON(http.StatusOK, contract)
+}
+
+// DeleteContract godoc
+// @Summary Delete a contract by ID
+// @Tags Contracts
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/contracts/{id} [delete]
+func DeleteContract(c *gin.Context) {
+	id := c.Param("id")
+	var contract models.Contract
+	if err := config.DB.First(&contract, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return
+	}
+	if err := config.DB.Delete(&contract).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Contract deleted"})
+}
+
+// GetContractRenewals godoc
+// @Summary List contracts ending or reaching their notice deadline soon
+// @Tags Contracts
+// @Produce json
+// @Param within query string false "Look-ahead window, e.g. 60d, 8w (default 60d)"
+// @Success 200 {array} models.ContractRenewal
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/contracts/renewals [get]
+func GetContractRenewals(c *gin.Context) {
+	within, err := utils.ParseWithin(c.DefaultQuery("within", "60d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	renewals, err := utils.FindUpcomingRenewals(within)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, renewals)
+}
+
+// validateContract checks required fields and references, returning an error message if invalid
+func validateContract(contract *models.Contract) string {
+	if contract.ContractNumber == "" {
+		return "Contract number is required"
+	}
+	if contract.StartDate.IsZero() || contract.EndDate.IsZero() {
+		return "Start date and end date are required"
+	}
+	if !contract.EndDate.After(contract.StartDate) {
+		return "End date must be after start date"
+	}
+	if contract.NoticePeriodDays < 0 || contract.SeatCount < 0 || contract.Value < 0 {
+		return "Notice period, seat count and value cannot be negative"
+	}
+
+	var vendor models.Vendor
+	if err := config.DB.First(&vendor, contract.VendorID).Error; err != nil {
+		return "Vendor not found"
+	}
+	var software models.Software
+	if err := config.DB.First(&software, contract.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	return ""
+}
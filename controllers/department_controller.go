@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"software_management/config"
 	"software_management/models"
+	"software_management/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -74,6 +76,10 @@ func CreateDepartment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if msg := validateDepartmentBudget(&department); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
 	config.DB.Create(&department)
 	c.JSON(http.StatusCreated, department)
 }
@@ -102,6 +108,10 @@ func UpdateDepartment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if msg := validateDepartmentBudget(&department); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
 
 	config.DB.Save(&department)
 	c.JSON(http.StatusOK, department)
@@ -151,3 +161,46 @@ func GetTeamsByDepartment(c *gin.Context) {
 
 	c.JSON(http.StatusOK, teams)
 }
+
+// GetDepartmentBudget godoc
+// @Summary Get a department's software budget, spend and forecast
+// @Description Compares the annual budget with spend so far this year and the forecast for the rest of the year at the committed monthly cost of current assignments
+// @Tags Departments
+// @Produce json
+// @Param id path int true "Department ID"
+// @Success 200 {object} models.BudgetStatus
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/departments/{id}/budget [get]
+func GetDepartmentBudget(c *gin.Context) {
+	deptID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department ID"})
+		return
+	}
+
+	var department models.Department
+	if err := config.DB.First(&department, deptID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	status, err := utils.GetDepartmentBudget(department.ID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// validateDepartmentBudget returns a message describing what is wrong with a department's budget settings, if anything
+func validateDepartmentBudget(department *models.Department) string {
+	if department.AnnualBudget < 0 {
+		return "Annual budget cannot be negative"
+	}
+	if department.BudgetEnforcement != "" && department.BudgetEnforcement != utils.BudgetWarn && department.BudgetEnforcement != utils.BudgetReject {
+		return "Budget enforcement must be warn or reject"
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLicenseKeysForSoftware godoc
+// @Summary List the license keys of a software (plaintext is never included)
+// @Tags License Keys
+// @Produce json
+// @Param id path int true "Software ID"
+// @Param status query string false "Filter by status: free or allocated"
+// @Success 200 {array} models.LicenseKey
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/license-keys [get]
+func GetLicenseKeysForSoftware(c *gin.Context) {
+	softwareID := c.Param("id")
+	var keys []models.LicenseKey
+
+	query := config.DB.Where("software_id = ?", softwareID)
+	switch c.Query("status") {
+	case "free":
+		query = query.Where("assigned_software_id IS NULL")
+	case "allocated":
+		query = query.Where("assigned_software_id IS NOT NULL")
+	}
+
+	if err := query.Order("id ASC").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateLicenseKeys godoc
+// @Summary Add license keys to a software's pool (encrypted at rest)
+// @Tags License Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "Software ID"
+// @Param keys body object true "Keys to add: {\"label\": \"...\", \"keys\": [\"...\"]}"
+// @Success 201 {array} models.LicenseKey
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/license-keys [post]
+func CreateLicenseKeys(c *gin.Context) {
+	var input struct {
+		Label string   `json:"label"`
+		Keys  []string `json:"keys"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(input.Keys) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one key is required"})
+		return
+	}
+
+	var software models.Software
+	if err := config.DB.First(&software, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Software not found"})
+		return
+	}
+
+	created := []models.LicenseKey{}
+	for _, plaintext := range input.Keys {
+		if plaintext == "" {
+			continue
+		}
+		key, err := utils.AddLicenseKey(software.ID, input.Label, plaintext)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "created": created})
+			return
+		}
+		created = append(created, key)
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// DeleteLicenseKey godoc
+// @Summary Remove a free license key from the pool
+// @Tags License Keys
+// @Produce json
+// @Param id path int true "License Key ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/license-keys/{id} [delete]
+func DeleteLicenseKey(c *gin.Context) {
+	var key models.LicenseKey
+	if err := config.DB.First(&key, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "License key not found"})
+		return
+	}
+	if key.AssignedSoftwareID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "License key is allocated to an assignment; revoke the assignment first"})
+		return
+	}
+	if err := config.DB.Delete(&key).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "License key deleted"})
+}
+
+// RevealLicenseKey godoc
+// @Summary Reveal a license key in plaintext (audited)
+// @Description Decrypts the key and records who asked for it and why in the key's access log
+// @Tags License Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "License Key ID"
+// @Param request body object true "Reveal request: {\"requested_by\": 2, \"reason\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/license-keys/{id}/reveal [post]
+func RevealLicenseKey(c *gin.Context) {
+	var input struct {
+		RequestedBy uint   `json:"requested_by"`
+		Reason      string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.RequestedBy == 0 || input.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "requested_by and reason are required"})
+		return
+	}
+
+	var key models.LicenseKey
+	if err := config.DB.First(&key, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "License key not found"})
+		return
+	}
+
+	plaintext, err := utils.RevealLicenseKey(key, input.RequestedBy, input.Reason, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, utils.ErrVaultKeyMissing) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reveal license key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          key.ID,
+		"software_id": key.SoftwareID,
+		"label":       key.Label,
+		"key":         plaintext,
+	})
+}
+
+// GetLicenseKeyAccessLogs godoc
+// @Summary Get the reveal audit trail of a license key
+// @Tags License Keys
+// @Produce json
+// @Param id path int true "License Key ID"
+// @Param limit query int false "Limit results"
+// @Param offset query int false "Offset results"
+// @Success 200 {array} models.LicenseKeyAccessLog
+// @Failure 500 {object} models.APIResponse
+// @Router /api/license-keys/{id}/access-logs [get]
+func GetLicenseKeyAccessLogs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	var logs []models.LicenseKeyAccessLog
+	if err := config.DB.Where("license_key_id = ?", c.Param("id")).
+		Order("accessed_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, logs)
+}
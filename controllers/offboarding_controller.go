@@ -1,47 +1,460 @@
 package controllers
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"software_management/config"
 	"software_management/models"
 	"software_management/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // OffboardStaff godoc
 // @Summary Offboard staff (revoke all software, optionally mark as inactive)
+// @Description Offboards the staff member right away: every software they hold is revoked, manually assigned software included, and they are marked inactive. An offboarding already scheduled for them is brought forward and runs as planned. Backup owners take over the software the staff member owns. While they still own software with no active backup owner, offboarding is refused with 409 and the software listed, unless force is set; forced offboarding answers with a warning instead.
 // @Tags Staff
 // @Produce json
 // @Param id path int true "Staff ID"
+// @Param force query bool false "Offboard even when software would be left without an active owner"
 // @Success 200 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
-// @Router /api/staff/{id}/offboard [post]
+// @Router /api/staff/{id}/offboard [put]
 func OffboardStaff(c *gin.Context) {
-	// OffboardStaff revokes all auto-assigned software and optionally marks staff as inactive
 	id := c.Param("id")
 	var staff models.Staff
 	if err := config.DB.First(&staff, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Staff not found"})
 		return
 	}
+	unbacked, ok := checkUnbackedOwnership(c, staff.ID)
+	if !ok {
+		return
+	}
 
-	// Revoke software
-	if err := utils.RevokeSoftwareAssignmentsForStaff(staff.ID); err != nil {
+	var offboarding models.Offboarding
+	err := config.DB.Where("staff_id = ? AND status IN ?", staff.ID, []string{utils.OffboardingScheduled, utils.OffboardingInProgress}).
+		First(&offboarding).Error
+	if err == nil {
+		if err = forceOffboarding(c, &offboarding); err == nil {
+			err = utils.ExecuteOffboarding(&offboarding)
+		}
+	} else {
+		offboarding = models.Offboarding{StaffID: staff.ID, EffectiveDate: time.Now(), ManualHandling: utils.HandleRevoke, Forced: forced(c)}
+		err = utils.ScheduleOffboarding(&offboarding, nil)
+	}
+	if err != nil {
+		if errors.Is(err, utils.ErrUnbackedOwnership) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke software"})
 		return
 	}
 
-	// Optionally mark as inactive (if not already)
-	if staff.Status != "inactive" {
-		staff.Status = "inactive"
-		if err := config.DB.Save(&staff).Error; err != nil {
-			log.Println("Failed to mark staff inactive during offboarding:", err)
+	response := gin.H{"message": "Staff offboarded and all software revoked", "offboarding": offboarding}
+	if offboarding.Status != utils.OffboardingCompleted {
+		response["message"] = "Staff offboarded, but some software could not be deprovisioned and will be retried"
+	}
+	if len(unbacked) > 0 {
+		response["warning"] = "Software is still owned by the offboarded staff member and needs a new owner"
+		response["software"] = unbacked
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ScheduleStaffOffboarding godoc
+// @Summary Schedule the offboarding of a staff member
+// @Description Plans the deprovisioning of everything the staff member holds for their last day. Software granted by matches and rules is revoked; manually assigned software is revoked, transferred to another staff member or kept with a reason, following manual_handling unless an override names it. Without an effective date, or with one in the past, the offboarding runs right away. Ownership is checked as for immediate offboarding, and again when the offboarding runs unless it was forced.
+// @Tags Offboarding
+// @Accept json
+// @Produce json
+// @Param id path int true "Staff ID"
+// @Param force query bool false "Offboard even when software would be left without an active owner"
+// @Param plan body models.OffboardingPlan true "Effective date, default handling of manual assignments and per-software overrides"
+// @Success 201 {object} models.Offboarding
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/staff/{id}/offboarding [post]
+func ScheduleStaffOffboarding(c *gin.Context) {
+	var staff models.Staff
+	if err := config.DB.First(&staff, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Staff not found"})
+		return
+	}
+	var plan models.OffboardingPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateOffboardingPlan(staff, &plan); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if _, ok := checkUnbackedOwnership(c, staff.ID); !ok {
+		return
+	}
+
+	offboarding := models.Offboarding{
+		StaffID:        staff.ID,
+		EffectiveDate:  *plan.EffectiveDate,
+		ManualHandling: plan.ManualHandling,
+		TransferToID:   plan.TransferToID,
+		Reason:         plan.Reason,
+		Forced:         forced(c),
+	}
+	if err := utils.ScheduleOffboarding(&offboarding, plan.Overrides); err != nil {
+		if errors.Is(err, utils.ErrOffboardingOpen) || errors.Is(err, utils.ErrUnbackedOwnership) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, offboarding)
+}
+
+// GetOffboardings godoc
+// @Summary List offboardings
+// @Tags Offboarding
+// @Produce json
+// @Param status query string false "Filter by status (scheduled/in_progress/completed/cancelled)"
+// @Success 200 {array} models.Offboarding
+// @Failure 500 {object} models.APIResponse
+// @Router /api/offboardings [get]
+func GetOffboardings(c *gin.Context) {
+	var offboardings []models.Offboarding
+	query := config.DB.Preload("Staff").Order("effective_date DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", strings.ToLower(status))
+	}
+	if err := query.Find(&offboardings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, offboardings)
+}
+
+// GetOffboardingByID godoc
+// @Summary Get an offboarding with the deprovisioning status of each software
+// @Tags Offboarding
+// @Produce json
+// @Param id path int true "Offboarding ID"
+// @Success 200 {object} models.Offboarding
+// @Failure 404 {object} models.APIResponse
+// @Router /api/offboardings/{id} [get]
+func GetOffboardingByID(c *gin.Context) {
+	var offboarding models.Offboarding
+	if err := preloadOffboardingItems().Preload("Staff").First(&offboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// GetOffboardingForStaff godoc
+// @Summary Get the latest offboarding of a staff member
+// @Tags Offboarding
+// @Produce json
+// @Param id path int true "Staff ID"
+// @Success 200 {object} models.Offboarding
+// @Failure 404 {object} models.APIResponse
+// @Router /api/staff/{id}/offboarding [get]
+func GetOffboardingForStaff(c *gin.Context) {
+	var offboarding models.Offboarding
+	if err := preloadOffboardingItems().Where("staff_id = ?", c.Param("id")).
+		Order("created_at DESC").First(&offboarding).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// ReplanOffboardingItem godoc
+// @Summary Change what is done with one software of an offboarding
+// @Description Only software not deprovisioned yet can be replanned, and only manually assigned software can be transferred or kept
+// @Tags Offboarding
+// @Accept json
+// @Produce json
+// @Param id path int true "Offboarding Item ID"
+// @Param action body models.OffboardingAction true "Revoke, transfer (with transfer_to_id) or keep (with reason)"
+// @Success 200 {object} models.OffboardingItem
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/offboarding-items/{id} [put]
+func ReplanOffboardingItem(c *gin.Context) {
+	var item models.OffboardingItem
+	if err := config.DB.First(&item, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding item not found"})
+		return
+	}
+	var input models.OffboardingAction
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var offboarding models.Offboarding
+	if err := config.DB.First(&offboarding, item.OffboardingID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	if msg := validateOffboardingAction(offboarding.StaffID, &input); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if item.Source != utils.SourceManual && input.Action != utils.HandleRevoke {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Software granted by matches and rules can only be revoked"})
+		return
+	}
+
+	if err := utils.ReplanOffboardingItem(&item, input.Action, input.TransferToID, input.Reason); err != nil {
+		if errors.Is(err, utils.ErrOffboardingClosed) || errors.Is(err, utils.ErrItemProcessed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// ExecuteOffboarding godoc
+// @Summary Run an offboarding now
+// @Description Deprovisions the software still pending or failed without waiting for the effective date. Ownership is checked as for immediate offboarding; force is recorded on the offboarding.
+// @Tags Offboarding
+// @Produce json
+// @Param id path int true "Offboarding ID"
+// @Param force query bool false "Offboard even when software would be left without an active owner"
+// @Success 200 {object} models.Offboarding
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/offboardings/{id}/execute [post]
+func ExecuteOffboarding(c *gin.Context) {
+	var offboarding models.Offboarding
+	if err := config.DB.First(&offboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	if !offboarding.Forced {
+		if _, ok := checkUnbackedOwnership(c, offboarding.StaffID); !ok {
+			return
+		}
+	}
+	if err := forceOffboarding(c, &offboarding); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := utils.ExecuteOffboarding(&offboarding); err != nil {
+		if errors.Is(err, utils.ErrOffboardingClosed) || errors.Is(err, utils.ErrUnbackedOwnership) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// CancelOffboarding godoc
+// @Summary Cancel a scheduled offboarding
+// @Description Only possible before anything has been deprovisioned
+// @Tags Offboarding
+// @Produce json
+// @Param id path int true "Offboarding ID"
+// @Success 200 {object} models.Offboarding
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/offboardings/{id}/cancel [post]
+func CancelOffboarding(c *gin.Context) {
+	var offboarding models.Offboarding
+	if err := config.DB.First(&offboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	if err := utils.CancelOffboarding(&offboarding); err != nil {
+		if errors.Is(err, utils.ErrOffboardingClosed) || errors.Is(err, utils.ErrOffboardingStarted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// GetOffboardingCertificate godoc
+// @Summary Get the certificate of a completed offboarding
+// @Description Lists every software revoked from, transferred away from or kept by the leaver, with the time each was processed
+// @Tags Offboarding
+// @Produce json
+// @Param id path int true "Offboarding ID"
+// @Success 200 {object} models.OffboardingCertificate
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/offboardings/{id}/certificate [get]
+func GetOffboardingCertificate(c *gin.Context) {
+	var offboarding models.Offboarding
+	if err := config.DB.First(&offboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+	certificate, err := utils.IssueOffboardingCertificate(offboarding)
+	if err != nil {
+		if errors.Is(err, utils.ErrOffboardingNotCompleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, certificate)
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Staff offboarded and all software revoked"})
+// preloadOffboardingItems loads the items of offboardings in the order they were planned
+func preloadOffboardingItems() *gorm.DB {
+	return config.DB.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// checkUnbackedOwnership refuses to offboard a staff member who owns software with no active backup
+// owner, unless force is set. It returns that software and whether offboarding may go ahead.
+func checkUnbackedOwnership(c *gin.Context, staffID uint) ([]models.Software, bool) {
+	// Software owned without a backup would be left without anyone approving access or answering for its cost
+	unbacked, err := utils.UnbackedSoftware(staffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if len(unbacked) > 0 && !forced(c) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "Staff member still owns software with no backup owner; reassign it or offboard with force=true",
+			"software": unbacked,
+		})
+		return nil, false
+	}
+	return unbacked, true
+}
+
+// forced reports whether the request asks to offboard regardless of software ownership
+func forced(c *gin.Context) bool {
+	force, _ := strconv.ParseBool(c.Query("force"))
+	return force
+}
+
+// forceOffboarding records a forced request on an existing offboarding, so that it runs, now and on
+// later retries, without the ownership check
+func forceOffboarding(c *gin.Context, offboarding *models.Offboarding) error {
+	if offboarding.Forced || !forced(c) {
+		return nil
+	}
+	offboarding.Forced = true
+	return config.DB.Model(&models.Offboarding{}).Where("id = ?", offboarding.ID).Update("forced", true).Error
+}
+
+// validateOffboardingPlan normalises an offboarding plan and returns a message describing what is
+// wrong with it, if anything
+func validateOffboardingPlan(staff models.Staff, plan *models.OffboardingPlan) string {
+	if plan.EffectiveDate == nil {
+		now := time.Now()
+		plan.EffectiveDate = &now
+	}
+	handling, ok := utils.NormalizeHandling(plan.ManualHandling)
+	if !ok {
+		return "Manual handling must be revoke, transfer or keep"
+	}
+	plan.ManualHandling = handling
+	plan.Reason = strings.TrimSpace(plan.Reason)
+	if handling == utils.HandleTransfer {
+		if msg := validateRecipient(staff.ID, plan.TransferToID); msg != "" {
+			return msg
+		}
+	} else {
+		plan.TransferToID = nil
+	}
+	if handling == utils.HandleKeep && plan.Reason == "" {
+		return "A reason is required to keep manually assigned software"
+	}
+
+	seen := make(map[uint]bool, len(plan.Overrides))
+	for i := range plan.Overrides {
+		override := &plan.Overrides[i]
+		if seen[override.SoftwareID] {
+			return fmt.Sprintf("Software %d is listed more than once", override.SoftwareID)
+		}
+		seen[override.SoftwareID] = true
+
+		var assignment models.AssignedSoftware
+		if err := config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, override.SoftwareID).
+			First(&assignment).Error; err != nil {
+			return fmt.Sprintf("Staff member does not hold software %d", override.SoftwareID)
+		}
+		if msg := validateOffboardingAction(staff.ID, override); msg != "" {
+			return msg
+		}
+		if override.Action != utils.HandleRevoke {
+			manual, err := utils.HasManualGrant(assignment)
+			if err != nil {
+				return err.Error()
+			}
+			if !manual {
+				return fmt.Sprintf("Software %d is granted by matches and rules and can only be revoked", override.SoftwareID)
+			}
+		}
+	}
+	return ""
+}
+
+// validateOffboardingAction normalises what to do with one software of a leaver and returns a message
+// describing what is wrong with it, if anything
+func validateOffboardingAction(staffID uint, action *models.OffboardingAction) string {
+	handling, ok := utils.NormalizeHandling(action.Action)
+	if !ok || action.Action == "" {
+		return "Action must be revoke, transfer or keep"
+	}
+	action.Action = handling
+	action.Reason = strings.TrimSpace(action.Reason)
+	switch handling {
+	case utils.HandleTransfer:
+		return validateRecipient(staffID, action.TransferToID)
+	case utils.HandleKeep:
+		action.TransferToID = nil
+		if action.Reason == "" {
+			return "A reason is required to keep software"
+		}
+	default:
+		action.TransferToID = nil
+	}
+	return ""
+}
+
+// validateRecipient returns a message describing why software cannot be transferred to a staff member, if anything
+func validateRecipient(leaverID uint, recipientID *uint) string {
+	if recipientID == nil {
+		return "transfer_to_id is required to transfer software"
+	}
+	if *recipientID == leaverID {
+		return "Software cannot be transferred to the staff member being offboarded"
+	}
+	var recipient models.Staff
+	if err := config.DB.First(&recipient, *recipientID).Error; err != nil {
+		return "Recipient not found"
+	}
+	if strings.EqualFold(recipient.Status, "inactive") {
+		return "Recipient has been offboarded"
+	}
+	return ""
 }
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// preloadOnboardingItems loads the checklist items of onboardings in the order they were added
+func preloadOnboardingItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// GetOnboardings godoc
+// @Summary List onboardings
+// @Tags Onboarding
+// @Produce json
+// @Param status query string false "Filter by status (open/completed)"
+// @Success 200 {array} models.Onboarding
+// @Failure 500 {object} models.APIResponse
+// @Router /api/onboardings [get]
+func GetOnboardings(c *gin.Context) {
+	var onboardings []models.Onboarding
+	query := preloadOnboardingItems(config.DB).Preload("Staff").Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", strings.ToLower(status))
+	}
+	if err := query.Find(&onboardings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, onboardings)
+}
+
+// GetOnboardingByID godoc
+// @Summary Get an onboarding with its checklist
+// @Tags Onboarding
+// @Produce json
+// @Param id path int true "Onboarding ID"
+// @Success 200 {object} models.Onboarding
+// @Failure 404 {object} models.APIResponse
+// @Router /api/onboardings/{id} [get]
+func GetOnboardingByID(c *gin.Context) {
+	var onboarding models.Onboarding
+	if err := preloadOnboardingItems(config.DB).Preload("Staff").First(&onboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding not found"})
+		return
+	}
+	c.JSON(http.StatusOK, onboarding)
+}
+
+// GetOnboardingForStaff godoc
+// @Summary Get the onboarding of a staff member
+// @Tags Onboarding
+// @Produce json
+// @Param id path int true "Staff ID"
+// @Success 200 {object} models.Onboarding
+// @Failure 404 {object} models.APIResponse
+// @Router /api/staff/{id}/onboarding [get]
+func GetOnboardingForStaff(c *gin.Context) {
+	var onboarding models.Onboarding
+	if err := preloadOnboardingItems(config.DB).Where("staff_id = ?", c.Param("id")).First(&onboarding).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding not found"})
+		return
+	}
+	c.JSON(http.StatusOK, onboarding)
+}
+
+// AddOnboardingTask godoc
+// @Summary Add a manual task to an open onboarding
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Param id path int true "Onboarding ID"
+// @Param body body object true "Task title, e.g. {\"title\": \"Issue laptop\"}"
+// @Success 201 {object} models.OnboardingItem
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/onboardings/{id}/tasks [post]
+func AddOnboardingTask(c *gin.Context) {
+	var onboarding models.Onboarding
+	if err := config.DB.First(&onboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding not found"})
+		return
+	}
+	var input struct {
+		Title string `json:"title" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if onboarding.Status != utils.OnboardingOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": utils.ErrOnboardingClosed.Error()})
+		return
+	}
+
+	task := models.OnboardingItem{
+		OnboardingID: onboarding.ID,
+		Kind:         utils.OnboardingTask,
+		Title:        strings.TrimSpace(input.Title),
+		Status:       utils.ItemPending,
+	}
+	if err := config.DB.Create(&task).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+// UpdateOnboardingItem godoc
+// @Summary Record the progress of an onboarding item
+// @Description Tasks can be marked done, skipped or pending again; software can only be skipped, since it is provisioned by assigning it. The onboarding completes once its last item is resolved.
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Param id path int true "Onboarding Item ID"
+// @Param body body object true "Status and optional detail, e.g. {\"status\": \"done\", \"detail\": \"MacBook Pro issued\"}"
+// @Success 200 {object} models.OnboardingItem
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/onboarding-items/{id} [put]
+func UpdateOnboardingItem(c *gin.Context) {
+	var item models.OnboardingItem
+	if err := config.DB.First(&item, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding item not found"})
+		return
+	}
+	var input struct {
+		Status string `json:"status" binding:"required"`
+		Detail string `json:"detail"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := strings.ToLower(input.Status)
+	if item.Kind == utils.OnboardingTask && status != utils.ItemDone && status != utils.ItemSkipped && status != utils.ItemPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Task status must be done, skipped or pending"})
+		return
+	}
+	if item.Kind == utils.OnboardingSoftware && status != utils.ItemSkipped {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Software can only be skipped by hand; sync the onboarding to retry it"})
+		return
+	}
+
+	if err := utils.UpdateOnboardingItem(&item, status, input.Detail); err != nil {
+		if errors.Is(err, utils.ErrOnboardingClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// SyncOnboarding godoc
+// @Summary Retry the software of an onboarding
+// @Description Re-runs auto-assignment for the staff member: failed software is retried and software they became entitled to since is added
+// @Tags Onboarding
+// @Produce json
+// @Param id path int true "Onboarding ID"
+// @Success 200 {object} models.Onboarding
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/onboardings/{id}/sync [post]
+func SyncOnboarding(c *gin.Context) {
+	var onboarding models.Onboarding
+	if err := preloadOnboardingItems(config.DB).First(&onboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding not found"})
+		return
+	}
+	if err := utils.SyncOnboarding(&onboarding); err != nil {
+		if errors.Is(err, utils.ErrOnboardingClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, onboarding)
+}
+
+// CompleteOnboarding godoc
+// @Summary Close an onboarding
+// @Description Refused with 409 while any software is pending or failed or any task is not done or skipped
+// @Tags Onboarding
+// @Produce json
+// @Param id path int true "Onboarding ID"
+// @Success 200 {object} models.Onboarding
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/onboardings/{id}/complete [post]
+func CompleteOnboarding(c *gin.Context) {
+	var onboarding models.Onboarding
+	if err := preloadOnboardingItems(config.DB).First(&onboarding, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Onboarding not found"})
+		return
+	}
+	if err := utils.CompleteOnboarding(&onboarding); err != nil {
+		if errors.Is(err, utils.ErrOnboardingIncomplete) {
+			open := []models.OnboardingItem{}
+			for _, item := range onboarding.Items {
+				if !utils.ItemResolved(item.Status) {
+					open = append(open, item)
+				}
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "items": open})
+			return
+		}
+		if errors.Is(err, utils.ErrOnboardingClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, onboarding)
+}
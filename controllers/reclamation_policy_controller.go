@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReclamationPolicies godoc
+// @Summary List seat reclamation policies
+// @Tags Reclamation Policies
+// @Produce json
+// @Success 200 {array} models.ReclamationPolicy
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reclamation-policies [get]
+func GetReclamationPolicies(c *gin.Context) {
+	var policies []models.ReclamationPolicy
+	if err := config.DB.Preload("Software").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// GetReclamationPolicy godoc
+// @Summary Get the seat reclamation policy of a software
+// @Tags Reclamation Policies
+// @Produce json
+// @Param id path int true "Software ID"
+// @Success 200 {object} models.ReclamationPolicy
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software/{id}/reclamation-policy [get]
+func GetReclamationPolicy(c *gin.Context) {
+	var policy models.ReclamationPolicy
+	if err := config.DB.Where("software_id = ?", c.Param("id")).First(&policy).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reclamation policy not found"})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// SetReclamationPolicy godoc
+// @Summary Create or replace the seat reclamation policy of a software
+// @Description Seats with no usage for idle_days are revoked, after warning the holder warning_days beforehand
+// @Tags Reclamation Policies
+// @Accept json
+// @Produce json
+// @Param id path int true "Software ID"
+// @Param policy body models.ReclamationPolicy true "Policy object"
+// @Success 200 {object} models.ReclamationPolicy
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/reclamation-policy [put]
+func SetReclamationPolicy(c *gin.Context) {
+	var software models.Software
+	if err := config.DB.First(&software, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Software not found"})
+		return
+	}
+
+	policy := models.ReclamationPolicy{IdleDays: 60, WarningDays: 7, Enabled: true}
+	config.DB.Where("software_id = ?", software.ID).First(&policy)
+
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	policy.SoftwareID = software.ID
+
+	if policy.IdleDays <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "idle_days must be greater than zero"})
+		return
+	}
+	if policy.WarningDays < 0 || policy.WarningDays > policy.IdleDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "warning_days must be between 0 and idle_days"})
+		return
+	}
+
+	if err := config.DB.Save(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteReclamationPolicy godoc
+// @Summary Remove the seat reclamation policy of a software
+// @Tags Reclamation Policies
+// @Produce json
+// @Param id path int true "Software ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/reclamation-policy [delete]
+func DeleteReclamationPolicy(c *gin.Context) {
+	if err := config.DB.Where("software_id = ?", c.Param("id")).Delete(&models.ReclamationPolicy{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reclamation policy deleted"})
+}
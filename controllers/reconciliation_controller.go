@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDriftReport godoc
+// @Summary Report drift between desired and actual software assignments
+// @Description Works out each staff member's desired software from all matches and rules and lists missing auto-assignments, stale auto-assignments whose match or rule is gone, and assignments held by inactive staff. Nothing is changed.
+// @Tags Reconciliation
+// @Produce json
+// @Param staff_id query int false "Only check this staff member"
+// @Success 200 {object} models.DriftReport
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reconciliation/drift [get]
+func GetDriftReport(c *gin.Context) {
+	reconcile(c, false)
+}
+
+// ApplyReconciliation godoc
+// @Summary Fix drift between desired and actual software assignments
+// @Description Creates missing auto-assignments and revokes stale ones and those of inactive staff, writing assignment logs. Returns the drift found and whether each item was fixed.
+// @Tags Reconciliation
+// @Produce json
+// @Param staff_id query int false "Only reconcile this staff member"
+// @Success 200 {object} models.DriftReport
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reconciliation/apply [post]
+func ApplyReconciliation(c *gin.Context) {
+	reconcile(c, true)
+}
+
+func reconcile(c *gin.Context, apply bool) {
+	staffID, err := strconv.Atoi(c.DefaultQuery("staff_id", "0"))
+	if err != nil || staffID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff_id"})
+		return
+	}
+
+	report, err := utils.Reconcile(uint(staffID), apply)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
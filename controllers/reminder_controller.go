@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReminders godoc
+// @Summary List reminders that have fired
+// @Description Retrieves fired reminders, newest first, optionally filtered by kind, contract, department and date
+// @Tags Reminders
+// @Produce json
+// @Param kind query string false "Filter by reminder kind"
+// @Param contract_id query int false "Filter by Contract ID"
+// @Param department_id query int false "Filter by Department ID"
+// @Param start query string false "Fired on or after (YYYY-MM-DD)"
+// @Param end query string false "Fired before (YYYY-MM-DD)"
+// @Param limit query int false "Limit results"
+// @Param offset query int false "Offset results"
+// @Success 200 {array} models.Reminder
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reminders [get]
+func GetReminders(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	var reminders []models.Reminder
+	query := config.DB.Preload("Contract").Preload("Contract.Software")
+
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if contract := c.Query("contract_id"); contract != "" {
+		query = query.Where("contract_id = ?", contract)
+	}
+	if dept := c.Query("department_id"); dept != "" {
+		query = query.Where("department_id = ?", dept)
+	}
+	if start, err := time.Parse("2006-01-02", c.Query("start")); err == nil {
+		query = query.Where("fired_at >= ?", start)
+	}
+	if end, err := time.Parse("2006-01-02", c.Query("end")); err == nil {
+		query = query.Where("fired_at < ?", end)
+	}
+
+	if err := query.Order("fired_at DESC").Limit(limit).Offset(offset).Find(&reminders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reminders)
+}
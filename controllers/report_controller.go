@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSpendReport godoc
+// @Summary Get monthly and annual software spend per department, team and software
+// @Tags Reports
+// @Produce json
+// @Param department_id query int false "Filter by Department ID"
+// @Param team_id query int false "Filter by Team ID"
+// @Param software_id query int false "Filter by Software ID"
+// @Success 200 {object} models.SpendReport
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reports/spend [get]
+func GetSpendReport(c *gin.Context) {
+	var rows []models.SpendRow
+
+	query := config.DB.Table("assigned_software").
+		Select(`
+			staff.department_id,
+			departments.name AS department,
+			staff.team_id,
+			teams.name AS team,
+			software.id AS software_id,
+			software.name AS software,
+			COALESCE(software_plans.seat_price, software.seat_price) AS seat_price,
+			COALESCE(software_plans.billing_period, software.billing_period) AS billing_period,
+			COALESCE(software_plans.currency, software.currency) AS currency,
+			COUNT(*) AS seats
+		`).
+		Joins("JOIN staff ON staff.id = assigned_software.staff_id").
+		Joins("JOIN software ON software.id = assigned_software.software_id").
+		Joins("LEFT JOIN software_plans ON software_plans.id = assigned_software.plan_id").
+		Joins("LEFT JOIN departments ON departments.id = staff.department_id").
+		Joins("LEFT JOIN teams ON teams.id = staff.team_id").
+		Where("assigned_software.status <> ?", utils.AssignmentPending)
+
+	if dept := c.Query("department_id"); dept != "" {
+		query = query.Where("staff.department_id = ?", dept)
+	}
+	if team := c.Query("team_id"); team != "" {
+		query = query.Where("staff.team_id = ?", team)
+	}
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software.id = ?", sw)
+	}
+
+	err := query.Group(`
+			staff.department_id, departments.name, staff.team_id, teams.name,
+			software.id, software.name, software.seat_price, software.billing_period, software.currency,
+			software_plans.seat_price, software_plans.billing_period, software_plans.currency
+		`).
+		Scan(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.BuildSpendReport(rows))
+}
+
+// GetChargebackReport godoc
+// @Summary Get the chargeback allocation of software cost per department for a billing period
+// @Description Splits each software's cost across departments by seat-days held in the period, prorating mid-period joins and leaves from the assignment logs
+// @Tags Reports
+// @Produce json
+// @Produce text/csv
+// @Param period query string false "Billing period (YYYY-MM), defaults to the current month"
+// @Param format query string false "Response format: json (default) or csv"
+// @Success 200 {array} models.ChargebackLine
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reports/chargeback [get]
+func GetChargebackReport(c *gin.Context) {
+	period := c.DefaultQuery("period", time.Now().Format("2006-01"))
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+	if _, _, err := utils.ParseBillingPeriod(period); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lines, err := utils.BuildChargeback(period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=chargeback-%s.json", period))
+		c.JSON(http.StatusOK, lines)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=chargeback-%s.csv", period))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"period", "department_id", "department", "software_id", "software", "currency", "seat_days", "average_seats", "share", "cost"})
+	for _, line := range lines {
+		_ = w.Write([]string{
+			line.Period,
+			fmt.Sprint(line.DepartmentID),
+			line.Department,
+			fmt.Sprint(line.SoftwareID),
+			line.Software,
+			line.Currency,
+			fmt.Sprintf("%.2f", line.SeatDays),
+			fmt.Sprintf("%.2f", line.AverageSeats),
+			fmt.Sprintf("%.4f", line.Share),
+			fmt.Sprintf("%.2f", line.Cost),
+		})
+	}
+	w.Flush()
+}
+
+// GetIdleSeatReport godoc
+// @Summary List idle seats and what they cost
+// @Description Lists assignments with no recorded usage for at least `days` days. Without `days`, each software's reclamation policy threshold is used, or 60 days when it has none.
+// @Tags Reports
+// @Produce json
+// @Param days query int false "Minimum idle days"
+// @Param software_id query int false "Filter by Software ID"
+// @Success 200 {object} models.IdleSeatReport
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/reports/idle-seats [get]
+func GetIdleSeatReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "0"))
+	if err != nil || days < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a non-negative number"})
+		return
+	}
+	softwareID, err := strconv.Atoi(c.DefaultQuery("software_id", "0"))
+	if err != nil || softwareID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid software_id"})
+		return
+	}
+
+	report, err := utils.FindIdleSeats(uint(softwareID), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"software_management/config"
 	"software_management/models"
+	"software_management/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -121,10 +123,13 @@ func GetSoftwareAssignmentByID(c *gin.Context) {
 
 // CreateSoftwareAssignment godoc
 // @Summary Create a new software assignment rule
+// @Description Creates the rule and assigns the software to every staff member in its scope
 // @Tags Software Assignment Rules
 // @Accept json
 // @Produce json
 // @Param assignment body models.SoftwareAssignment true "Software assignment rule"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
 // @Success 201 {object} models.SoftwareAssignment
 // @Failure 400 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
@@ -135,15 +140,41 @@ func CreateSoftwareAssignment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if msg := validateAssignmentRule(&assignment); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if isDryRun(c) {
+		staffList, err := utils.StaffCoveredByRule(assignment)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		preview, err := utils.PreviewAutoAssign(assignment.SoftwareID, assignment.PlanID, staffList, utils.SourceRule)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
 	if err := config.DB.Create(&assignment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	if err := utils.ApplyAssignmentRule(assignment); err != nil {
+		log.Println("Failed to apply assignment rule:", err)
+	}
+
 	c.JSON(http.StatusCreated, assignment)
 }
 
 // UpdateSoftwareAssignment godoc
 // @Summary Update an existing software assignment rule
+// @Description Staff no longer in the rule's scope lose the access it granted, and staff newly in scope are assigned the software
 // @Tags Software Assignment Rules
 // @Accept json
 // @Produce json
@@ -152,6 +183,7 @@ func CreateSoftwareAssignment(c *gin.Context) {
 // @Success 200 {object} models.SoftwareAssignment
 // @Failure 400 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
 // @Router /api/software-assignments/{id} [put]
 func UpdateSoftwareAssignment(c *gin.Context) {
 	var assignment models.SoftwareAssignment
@@ -161,11 +193,26 @@ func UpdateSoftwareAssignment(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
 		return
 	}
+	old := assignment
+
 	if err := c.ShouldBindJSON(&assignment); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	config.DB.Save(&assignment)
+	assignment.ID = old.ID
+	if msg := validateAssignmentRule(&assignment); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := config.DB.Save(&assignment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.SyncAssignmentRule(old, assignment); err != nil {
+		log.Println("Failed to sync assignment rule:", err)
+	}
+
 	c.JSON(http.StatusOK, assignment)
 }
 
@@ -190,6 +237,7 @@ func DeleteSoftwareAssignmentWithForce(c *gin.Context) {
 
 // DeleteSoftwareAssignment godoc
 // @Summary Delete a software assignment rule and revoke related assignments with logging
+// @Description Only staff in the rule's scope who got the software through a rule lose it, and only when no other rule or match still grants it
 // @Tags Software Assignment Rules
 // @Produce json
 // @Param id path int true "Assignment Rule ID"
@@ -206,30 +254,38 @@ func DeleteSoftwareAssignment(c *gin.Context) {
 		return
 	}
 
-	var assigned []models.AssignedSoftware
-	if err := config.DB.Where("software_id = ?", assignment.SoftwareID).Find(&assigned).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find assigned software"})
-		return
-	}
-
-	// Revoke each assignment and log
-	for _, a := range assigned {
-		log := models.SoftwareAssignmentLog{
-			StaffID:    a.StaffID,
-			SoftwareID: a.SoftwareID,
-			Action:     "Unassigned (Rule Deleted)",
-			ChangedBy:  0, // Placeholder for current user
-			ChangedAt:  time.Now(),
-		}
-		config.DB.Create(&log)
-		config.DB.Delete(&a)
-	}
-
 	// Delete the assignment rule
 	if err := config.DB.Delete(&assignment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete assignment rule"})
 		return
 	}
 
+	// Revoke the access it granted from the staff in its scope, with logging
+	if err := utils.RevokeAssignmentRule(assignment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke assignments of the rule"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Assignment rule and related assignments deleted"})
 }
+
+// validateAssignmentRule normalises the scope type of a rule and returns a message describing what is wrong with it, if anything
+func validateAssignmentRule(rule *models.SoftwareAssignment) string {
+	scope, ok := utils.NormalizeRuleScope(rule.ScopeType)
+	if !ok {
+		return "Scope type must be Department, Team or Staff"
+	}
+	rule.ScopeType = scope
+	if rule.ScopeID == 0 {
+		return "Scope ID is required"
+	}
+
+	var software models.Software
+	if err := config.DB.First(&software, rule.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	if err := utils.ValidatePlan(rule.SoftwareID, rule.PlanID); err != nil {
+		return err.Error()
+	}
+	return ""
+}
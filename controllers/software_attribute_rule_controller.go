@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSoftwareAttributeRules godoc
+// @Summary List attribute-based assignment rules
+// @Tags Software Attribute Rules
+// @Produce json
+// @Param software_id query int false "Filter by Software ID"
+// @Success 200 {array} models.SoftwareAttributeRule
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-attribute-rules [get]
+func GetSoftwareAttributeRules(c *gin.Context) {
+	var rules []models.SoftwareAttributeRule
+	query := config.DB.Preload("Software")
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ?", sw)
+	}
+	if err := query.Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetSoftwareAttributeRuleByID godoc
+// @Summary Get an attribute-based assignment rule
+// @Tags Software Attribute Rules
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.SoftwareAttributeRule
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software-attribute-rules/{id} [get]
+func GetSoftwareAttributeRuleByID(c *gin.Context) {
+	var rule models.SoftwareAttributeRule
+	if err := config.DB.Preload("Software").First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateSoftwareAttributeRule godoc
+// @Summary Create an attribute-based assignment rule
+// @Description Creates the rule and assigns the software to every staff member matching its condition. Conditions compare first_name, last_name, email, status, employment_type, department, department_id, team and team_id using ==, !=, contains, starts_with, ends_with and in [...], combined with AND, OR, NOT and parentheses.
+// @Tags Software Attribute Rules
+// @Accept json
+// @Produce json
+// @Param rule body models.SoftwareAttributeRule true "Rule object"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
+// @Success 201 {object} models.SoftwareAttributeRule
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-attribute-rules [post]
+func CreateSoftwareAttributeRule(c *gin.Context) {
+	var rule models.SoftwareAttributeRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateAttributeRule(&rule); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if isDryRun(c) {
+		staffList, err := utils.StaffMatchingAttributeRule(rule)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		preview, err := utils.PreviewAutoAssign(rule.SoftwareID, rule.PlanID, staffList, utils.SourceRule)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.ApplyAttributeRule(rule); err != nil {
+		log.Println("Failed to apply attribute rule:", err)
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateSoftwareAttributeRule godoc
+// @Summary Update an attribute-based assignment rule
+// @Description Staff who no longer match lose the access the rule granted, and newly matching staff are assigned the software
+// @Tags Software Attribute Rules
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param rule body models.SoftwareAttributeRule true "Updated rule"
+// @Success 200 {object} models.SoftwareAttributeRule
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-attribute-rules/{id} [put]
+func UpdateSoftwareAttributeRule(c *gin.Context) {
+	var rule models.SoftwareAttributeRule
+	if err := config.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+	old := rule
+	oldStaff, err := utils.StaffMatchingAttributeRule(old)
+	if err != nil {
+		log.Printf("Stored condition of attribute rule %d does not parse: %v", old.ID, err)
+	}
+
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID = old.ID
+	if msg := validateAttributeRule(&rule); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := config.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.SyncAttributeRule(old.SoftwareID, oldStaff, rule); err != nil {
+		log.Println("Failed to sync attribute rule:", err)
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteSoftwareAttributeRule godoc
+// @Summary Delete an attribute-based assignment rule and revoke the access it granted
+// @Description Only staff matching the rule who got the software through a rule lose it, and only when no other rule or match still grants it
+// @Tags Software Attribute Rules
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-attribute-rules/{id} [delete]
+func DeleteSoftwareAttributeRule(c *gin.Context) {
+	var rule models.SoftwareAttributeRule
+	if err := config.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+	matched, err := utils.StaffMatchingAttributeRule(rule)
+	if err != nil {
+		log.Printf("Stored condition of attribute rule %d does not parse: %v", rule.ID, err)
+	}
+
+	if err := config.DB.Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rule"})
+		return
+	}
+	utils.RevokeAttributeRule(rule, matched)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted and access revoked"})
+}
+
+// validateAttributeRule returns a message describing what is wrong with an attribute rule, if anything
+func validateAttributeRule(rule *models.SoftwareAttributeRule) string {
+	if rule.Name == "" {
+		return "Rule name is required"
+	}
+	if _, err := utils.ParseCondition(rule.Condition); err != nil {
+		return "Invalid condition: " + err.Error()
+	}
+
+	var software models.Software
+	if err := config.DB.First(&software, rule.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	if err := utils.ValidatePlan(rule.SoftwareID, rule.PlanID); err != nil {
+		return err.Error()
+	}
+	return ""
+}
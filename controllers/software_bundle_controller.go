@@ -0,0 +1,470 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSoftwareBundles godoc
+// @Summary List software bundles with their items
+// @Tags Software Bundles
+// @Produce json
+// @Success 200 {array} models.SoftwareBundle
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles [get]
+func GetSoftwareBundles(c *gin.Context) {
+	var bundles []models.SoftwareBundle
+	if err := config.DB.Preload("Items.Software").Order("name").Find(&bundles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bundles)
+}
+
+// GetSoftwareBundleByID godoc
+// @Summary Get a software bundle with its items
+// @Tags Software Bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Success 200 {object} models.SoftwareBundle
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software-bundles/{id} [get]
+func GetSoftwareBundleByID(c *gin.Context) {
+	var bundle models.SoftwareBundle
+	if err := config.DB.Preload("Items.Software").First(&bundle, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
+
+// CreateSoftwareBundle godoc
+// @Summary Create a software bundle
+// @Tags Software Bundles
+// @Accept json
+// @Produce json
+// @Param bundle body models.SoftwareBundle true "Bundle with its items"
+// @Success 201 {object} models.SoftwareBundle
+// @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles [post]
+func CreateSoftwareBundle(c *gin.Context) {
+	var bundle models.SoftwareBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateSoftwareBundle(&bundle); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	var existing models.SoftwareBundle
+	if err := config.DB.Where("name = ?", bundle.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A bundle with this name already exists"})
+		return
+	}
+
+	if err := config.DB.Create(&bundle).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, bundle)
+}
+
+// UpdateSoftwareBundle godoc
+// @Summary Update a software bundle
+// @Description Replaces the bundle's items. Staff holding the bundle lose software dropped from it, unless something else grants it, and are assigned software added to it.
+// @Tags Software Bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Param bundle body models.SoftwareBundle true "Updated bundle with its items"
+// @Success 200 {object} models.SoftwareBundle
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles/{id} [put]
+func UpdateSoftwareBundle(c *gin.Context) {
+	var bundle models.SoftwareBundle
+	if err := config.DB.First(&bundle, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+	id := bundle.ID
+
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	bundle.ID = id
+	if msg := validateSoftwareBundle(&bundle); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bundle_id = ?", bundle.ID).Delete(&models.SoftwareBundleItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(&bundle).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	holders, err := utils.StaffHoldingBundle(bundle.ID)
+	if err != nil {
+		log.Println("Failed to load staff holding the bundle:", err)
+	}
+	utils.SyncBundleHolders(holders)
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// DeleteSoftwareBundle godoc
+// @Summary Delete a software bundle
+// @Description Takes the bundle away from the staff it was assigned to directly, revoking only the software it granted. Bundles still used in a match cannot be deleted.
+// @Tags Software Bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles/{id} [delete]
+func DeleteSoftwareBundle(c *gin.Context) {
+	var bundle models.SoftwareBundle
+	if err := config.DB.First(&bundle, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+
+	var matches int64
+	config.DB.Model(&models.SoftwareBundleMatch{}).Where("bundle_id = ?", bundle.ID).Count(&matches)
+	if matches > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bundle is used in matches; delete them first"})
+		return
+	}
+
+	var assignments []models.BundleAssignment
+	if err := config.DB.Where("bundle_id = ?", bundle.ID).Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, assignment := range assignments {
+		if err := utils.UnassignBundle(assignment); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke the bundle from its staff"})
+			return
+		}
+	}
+
+	if err := config.DB.Delete(&bundle).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bundle"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Bundle deleted and its software revoked from its staff"})
+}
+
+// AssignSoftwareBundle godoc
+// @Summary Assign a software bundle to a staff member
+// @Description Assigns every software of the bundle to the staff member, tagged with the bundle as source
+// @Tags Software Bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Param body body object true "Staff ID, e.g. {\"staff_id\": 2}"
+// @Param dry_run query bool false "Preview the per-software changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
+// @Success 201 {object} models.BundleAssignment
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles/{id}/assign [post]
+func AssignSoftwareBundle(c *gin.Context) {
+	var bundle models.SoftwareBundle
+	if err := config.DB.First(&bundle, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+
+	var input struct {
+		StaffID uint `json:"staff_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var staff models.Staff
+	if err := config.DB.First(&staff, input.StaffID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Staff not found"})
+		return
+	}
+
+	var existing models.BundleAssignment
+	if err := config.DB.Where("bundle_id = ? AND staff_id = ?", bundle.ID, staff.ID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Staff member already has this bundle"})
+		return
+	}
+
+	if isDryRun(c) {
+		preview, err := utils.PreviewBundleAssign(bundle, []models.Staff{staff}, utils.SourceBundle)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	assignment, err := utils.AssignBundle(bundle, staff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// UnassignSoftwareBundle godoc
+// @Summary Take a software bundle away from a staff member
+// @Description Revokes only the software the bundle granted; software also assigned manually or through a match or rule is kept
+// @Tags Software Bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Param staff_id path int true "Staff ID"
+// @Param dry_run query bool false "Preview the per-software changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundles/{id}/staff/{staff_id} [delete]
+func UnassignSoftwareBundle(c *gin.Context) {
+	var assignment models.BundleAssignment
+	if err := config.DB.Where("bundle_id = ? AND staff_id = ?", c.Param("id"), c.Param("staff_id")).
+		First(&assignment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Staff member does not have this bundle"})
+		return
+	}
+
+	if isDryRun(c) {
+		var bundle models.SoftwareBundle
+		config.DB.First(&bundle, assignment.BundleID)
+		var staff models.Staff
+		config.DB.First(&staff, assignment.StaffID)
+		preview, err := utils.PreviewBundleRevoke(bundle, []models.Staff{staff}, utils.SourceBundle, assignment.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := utils.UnassignBundle(assignment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Bundle revoked from staff member"})
+}
+
+// GetSoftwareBundleMatches godoc
+// @Summary List bundle matches
+// @Tags Software Bundles
+// @Produce json
+// @Param bundle_id query int false "Filter by Bundle ID"
+// @Success 200 {array} models.SoftwareBundleMatch
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundle-matches [get]
+func GetSoftwareBundleMatches(c *gin.Context) {
+	var matches []models.SoftwareBundleMatch
+	query := config.DB.Preload("Bundle")
+	if bundleID := c.Query("bundle_id"); bundleID != "" {
+		query = query.Where("bundle_id = ?", bundleID)
+	}
+	if err := query.Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// CreateSoftwareBundleMatch godoc
+// @Summary Match a bundle to the organization, a department or a team
+// @Description Creates the match and assigns every software of the bundle to all staff in its scope
+// @Tags Software Bundles
+// @Accept json
+// @Produce json
+// @Param match body models.SoftwareBundleMatch true "Bundle match"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
+// @Success 201 {object} models.SoftwareBundleMatch
+// @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundle-matches [post]
+func CreateSoftwareBundleMatch(c *gin.Context) {
+	var match models.SoftwareBundleMatch
+	if err := c.ShouldBindJSON(&match); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	match.Bundle = nil
+	if msg := validateSoftwareBundleMatch(&match); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	var existing models.SoftwareBundleMatch
+	if err := config.DB.Where("bundle_id = ? AND scope_type = ? AND scope_id = ?", match.BundleID, match.ScopeType, match.ScopeID).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match already exists"})
+		return
+	}
+
+	staffList, err := utils.StaffCoveredByBundleMatch(match)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if isDryRun(c) {
+		var bundle models.SoftwareBundle
+		config.DB.First(&bundle, match.BundleID)
+		preview, err := utils.PreviewBundleAssign(bundle, staffList, utils.SourceBundle)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Create(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := utils.ApplyBundleMatch(match, staffList); err != nil {
+		log.Println("Failed to apply bundle match:", err)
+	}
+
+	c.JSON(http.StatusCreated, match)
+}
+
+// DeleteSoftwareBundleMatch godoc
+// @Summary Delete a bundle match and revoke what it granted
+// @Description Staff in the match's scope lose each software of the bundle unless something else grants it too
+// @Tags Software Bundles
+// @Produce json
+// @Param id path int true "Bundle match ID"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-bundle-matches/{id} [delete]
+func DeleteSoftwareBundleMatch(c *gin.Context) {
+	var match models.SoftwareBundleMatch
+	if err := config.DB.First(&match, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	staffList, err := utils.StaffCoveredByBundleMatch(match)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if isDryRun(c) {
+		var bundle models.SoftwareBundle
+		config.DB.First(&bundle, match.BundleID)
+		preview, err := utils.PreviewBundleRevoke(bundle, staffList, utils.SourceBundleMatch, match.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Delete(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := utils.RevokeBundleMatch(match, staffList); err != nil {
+		log.Println("Failed to revoke bundle match:", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Match deleted and bundle revoked from its staff"})
+}
+
+// validateSoftwareBundle returns a message describing what is wrong with a bundle, if anything
+func validateSoftwareBundle(bundle *models.SoftwareBundle) string {
+	if bundle.Name == "" {
+		return "Name is required"
+	}
+	if len(bundle.Items) == 0 {
+		return "A bundle needs at least one software"
+	}
+	seen := make(map[uint]bool)
+	for i := range bundle.Items {
+		item := &bundle.Items[i]
+		item.ID = 0
+		item.BundleID = bundle.ID
+		item.Software = nil
+		if seen[item.SoftwareID] {
+			return "Software " + strconv.Itoa(int(item.SoftwareID)) + " is listed twice"
+		}
+		seen[item.SoftwareID] = true
+
+		var software models.Software
+		if err := config.DB.First(&software, item.SoftwareID).Error; err != nil {
+			return "Software " + strconv.Itoa(int(item.SoftwareID)) + " not found"
+		}
+		if err := utils.ValidatePlan(item.SoftwareID, item.PlanID); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// validateSoftwareBundleMatch normalises the scope type of a bundle match and returns a message
+// describing what is wrong with it, if anything
+func validateSoftwareBundleMatch(match *models.SoftwareBundleMatch) string {
+	var bundle models.SoftwareBundle
+	if err := config.DB.First(&bundle, match.BundleID).Error; err != nil {
+		return "Bundle not found"
+	}
+	scope, ok := utils.NormalizeBundleScope(match.ScopeType)
+	if !ok {
+		return "Scope type must be Organization, Department or Team"
+	}
+	match.ScopeType = scope
+
+	switch scope {
+	case utils.ScopeOrganization:
+		match.ScopeID = 0
+	case utils.ScopeDepartment:
+		var department models.Department
+		if err := config.DB.First(&department, match.ScopeID).Error; err != nil {
+			return "Department not found"
+		}
+	case utils.ScopeTeam:
+		var team models.Team
+		if err := config.DB.First(&team, match.ScopeID).Error; err != nil {
+			return "Team not found"
+		}
+	}
+	return ""
+}
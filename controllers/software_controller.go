@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"software_management/config"
 	"software_management/models"
+	"software_management/utils"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -110,6 +112,15 @@ func CreateSoftware(c *gin.Context) {
 		return
 	}
 
+	if msg := validateSoftwarePricing(&software); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if msg := validateSoftwareOwners(software); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
 	// Check for duplicate software name
 	var existing models.Software
 	if err := config.DB.Where("name = ?", software.Name).First(&existing).Error; err == nil {
@@ -144,7 +155,17 @@ func UpdateSoftware(c *gin.Context) {
 		return
 	}
 
-	var input models.Software
+	// Pricing fields left out of the request keep their current values
+	var input struct {
+		Name          string   `json:"name"`
+		Description   string   `json:"description"`
+		SeatLimit     *int     `json:"seat_limit"`
+		SeatPrice     *float64 `json:"seat_price"`
+		BillingPeriod *string  `json:"billing_period"`
+		Currency      *string  `json:"currency"`
+		OwnerID       *uint    `json:"owner_id"`
+		BackupOwnerID *uint    `json:"backup_owner_id"`
+	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -159,6 +180,28 @@ func UpdateSoftware(c *gin.Context) {
 
 	software.Name = input.Name
 	software.Description = input.Description // Add any other fields you use
+	if input.SeatLimit != nil {
+		software.SeatLimit = *input.SeatLimit
+	}
+	if input.SeatPrice != nil {
+		software.SeatPrice = *input.SeatPrice
+	}
+	if input.BillingPeriod != nil {
+		software.BillingPeriod = *input.BillingPeriod
+	}
+	if input.Currency != nil {
+		software.Currency = *input.Currency
+	}
+	software.OwnerID = input.OwnerID
+	software.BackupOwnerID = input.BackupOwnerID
+	if msg := validateSoftwarePricing(&software); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if msg := validateSoftwareOwners(software); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
 
 	if err := config.DB.Save(&software).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -200,7 +243,8 @@ func DeleteSoftware(c *gin.Context) {
 
 	now := time.Now()
 	for _, assignment := range assignments {
-		// Delete assignment
+		// Delete assignment and its grants
+		config.DB.Where("assigned_software_id = ?", assignment.ID).Delete(&models.AssignmentGrant{})
 		if err := config.DB.Delete(&assignment).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke assignment"})
 			return
@@ -218,7 +262,20 @@ func DeleteSoftware(c *gin.Context) {
 		config.DB.Create(&log)
 	}
 
-	// Step 2: Delete the software record itself
+	// Step 2: Drop the software from the bundles listing it
+	if err := config.DB.Where("software_id = ?", softwareID).Delete(&models.SoftwareBundleItem{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove software from its bundles"})
+		return
+	}
+
+	// Step 3: Drop the conflict and requires policies it takes part in
+	if err := config.DB.Where("software_id = ? OR related_software_id = ?", softwareID, softwareID).
+		Delete(&models.SoftwarePolicy{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete software policies"})
+		return
+	}
+
+	// Step 4: Delete the software record itself
 	if err := config.DB.Delete(&software).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete software"})
 		return
@@ -315,6 +372,31 @@ func GetStaffAssignedToSoftwareWithDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, staff)
 }
 
+// GetSoftwareSeats godoc
+// @Summary Get purchased, used and free seats for a software
+// @Tags Software
+// @Produce json
+// @Param id path int true "Software ID"
+// @Success 200 {object} models.SeatUsage
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software/{id}/seats [get]
+func GetSoftwareSeats(c *gin.Context) {
+	softwareID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid software ID"})
+		return
+	}
+
+	usage, err := utils.GetSeatUsage(uint(softwareID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Software not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
 // GetAllSoftwareNames godoc
 // @Summary Get a list of all software names
 // @Tags Software
@@ -352,3 +434,54 @@ func GetAllSoftwareSummaries(c *gin.Context) {
 
 	c.JSON(http.StatusOK, summaries)
 }
+
+// validateSoftwareOwners returns a message describing what is wrong with the owner and backup owner
+// of a software, if anything
+func validateSoftwareOwners(software models.Software) string {
+	for _, owner := range []struct {
+		id    *uint
+		label string
+	}{{software.OwnerID, "Owner"}, {software.BackupOwnerID, "Backup owner"}} {
+		if owner.id == nil {
+			continue
+		}
+		var staff models.Staff
+		if err := config.DB.First(&staff, *owner.id).Error; err != nil {
+			return owner.label + " not found"
+		}
+		if strings.EqualFold(staff.Status, "inactive") {
+			return owner.label + " has been offboarded"
+		}
+	}
+	if software.OwnerID != nil && software.BackupOwnerID != nil && *software.OwnerID == *software.BackupOwnerID {
+		return "Backup owner must differ from the owner"
+	}
+	return ""
+}
+
+// validateSoftwarePricing normalises the billing period and currency of a software and returns a
+// message describing what is wrong with its pricing, if anything
+func validateSoftwarePricing(software *models.Software) string {
+	if software.SeatLimit < 0 {
+		return "Seat limit cannot be negative; use 0 for unlimited seats"
+	}
+	if software.SeatPrice < 0 {
+		return "Seat price cannot be negative"
+	}
+	software.BillingPeriod = strings.ToLower(strings.TrimSpace(software.BillingPeriod))
+	switch software.BillingPeriod {
+	case "":
+		software.BillingPeriod = utils.BillingMonthly
+	case utils.BillingMonthly, utils.BillingQuarterly, utils.BillingAnnual:
+	default:
+		return "Billing period must be monthly, quarterly or annual"
+	}
+	software.Currency = strings.ToUpper(strings.TrimSpace(software.Currency))
+	if software.Currency == "" {
+		software.Currency = "USD"
+	}
+	if len(software.Currency) != 3 {
+		return "Currency must be a three-letter code"
+	}
+	return ""
+}
@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSoftwareExclusions godoc
+// @Summary List per-staff software exclusions
+// @Tags Software Exclusions
+// @Produce json
+// @Param staff_id query int false "Filter by Staff ID"
+// @Param software_id query int false "Filter by Software ID"
+// @Success 200 {array} models.SoftwareExclusion
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-exclusions [get]
+func GetSoftwareExclusions(c *gin.Context) {
+	var exclusions []models.SoftwareExclusion
+	query := config.DB.Preload("Software")
+	if staffID := c.Query("staff_id"); staffID != "" {
+		query = query.Where("staff_id = ?", staffID)
+	}
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ?", sw)
+	}
+	if err := query.Find(&exclusions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exclusions)
+}
+
+// GetSoftwareExclusionsForStaff godoc
+// @Summary List the software a staff member is excluded from
+// @Description Companion of the staff's assigned-software detail. Lapsed exclusions are included; their expires_at has passed.
+// @Tags Staff
+// @Produce json
+// @Param id path int true "Staff ID"
+// @Success 200 {array} models.SoftwareExclusion
+// @Failure 500 {object} models.APIResponse
+// @Router /api/staff/{id}/exclusions [get]
+func GetSoftwareExclusionsForStaff(c *gin.Context) {
+	var exclusions []models.SoftwareExclusion
+	if err := config.DB.Preload("Software").Where("staff_id = ?", c.Param("id")).
+		Order("id").Find(&exclusions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exclusions)
+}
+
+// GetSoftwareExclusionByID godoc
+// @Summary Get a software exclusion
+// @Tags Software Exclusions
+// @Produce json
+// @Param id path int true "Exclusion ID"
+// @Success 200 {object} models.SoftwareExclusion
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software-exclusions/{id} [get]
+func GetSoftwareExclusionByID(c *gin.Context) {
+	var exclusion models.SoftwareExclusion
+	if err := config.DB.Preload("Software").First(&exclusion, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exclusion not found"})
+		return
+	}
+	c.JSON(http.StatusOK, exclusion)
+}
+
+// CreateSoftwareExclusion godoc
+// @Summary Exclude a staff member from a software
+// @Description Organization, department and team matches and assignment rules no longer give the software to the staff member until the exclusion expires. Access they already got that way is revoked; manual assignments are kept.
+// @Tags Software Exclusions
+// @Accept json
+// @Produce json
+// @Param exclusion body models.SoftwareExclusion true "Exclusion object"
+// @Success 201 {object} models.SoftwareExclusion
+// @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-exclusions [post]
+func CreateSoftwareExclusion(c *gin.Context) {
+	var exclusion models.SoftwareExclusion
+	if err := c.ShouldBindJSON(&exclusion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exclusion.Software = nil
+	if msg := validateSoftwareExclusion(&exclusion); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	var existing models.SoftwareExclusion
+	if err := config.DB.Where("staff_id = ? AND software_id = ?", exclusion.StaffID, exclusion.SoftwareID).
+		First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Staff member is already excluded from this software"})
+		return
+	}
+
+	if err := config.DB.Create(&exclusion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := utils.ApplySoftwareExclusion(exclusion); err != nil {
+		log.Println("Failed to revoke excluded software:", err)
+	}
+
+	c.JSON(http.StatusCreated, exclusion)
+}
+
+// UpdateSoftwareExclusion godoc
+// @Summary Update a software exclusion
+// @Description Changing the staff member, software or expiry revokes or restores access accordingly
+// @Tags Software Exclusions
+// @Accept json
+// @Produce json
+// @Param id path int true "Exclusion ID"
+// @Param exclusion body models.SoftwareExclusion true "Updated exclusion"
+// @Success 200 {object} models.SoftwareExclusion
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-exclusions/{id} [put]
+func UpdateSoftwareExclusion(c *gin.Context) {
+	var exclusion models.SoftwareExclusion
+	if err := config.DB.First(&exclusion, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exclusion not found"})
+		return
+	}
+	old := exclusion
+
+	if err := c.ShouldBindJSON(&exclusion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exclusion.ID = old.ID
+	exclusion.Software = nil
+	if msg := validateSoftwareExclusion(&exclusion); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := config.DB.Save(&exclusion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Give back what the old exclusion withheld, then withhold what the new one covers
+	if err := utils.LiftSoftwareExclusion(old); err != nil {
+		log.Println("Failed to restore previously excluded software:", err)
+	}
+	if err := utils.ApplySoftwareExclusion(exclusion); err != nil {
+		log.Println("Failed to revoke excluded software:", err)
+	}
+
+	c.JSON(http.StatusOK, exclusion)
+}
+
+// DeleteSoftwareExclusion godoc
+// @Summary Remove a software exclusion
+// @Description Software the staff member's matches and rules grant is assigned to them again
+// @Tags Software Exclusions
+// @Produce json
+// @Param id path int true "Exclusion ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-exclusions/{id} [delete]
+func DeleteSoftwareExclusion(c *gin.Context) {
+	var exclusion models.SoftwareExclusion
+	if err := config.DB.First(&exclusion, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exclusion not found"})
+		return
+	}
+	if err := config.DB.Delete(&exclusion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete exclusion"})
+		return
+	}
+	if err := utils.LiftSoftwareExclusion(exclusion); err != nil {
+		log.Println("Failed to restore previously excluded software:", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exclusion removed"})
+}
+
+// validateSoftwareExclusion returns a message describing what is wrong with an exclusion, if anything
+func validateSoftwareExclusion(exclusion *models.SoftwareExclusion) string {
+	var staff models.Staff
+	if err := config.DB.First(&staff, exclusion.StaffID).Error; err != nil {
+		return "Staff not found"
+	}
+	var software models.Software
+	if err := config.DB.First(&software, exclusion.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	if exclusion.Reason == "" {
+		return "Reason is required"
+	}
+	return ""
+}
@@ -71,6 +71,11 @@ func CreateSoftwareOrganizationMatch(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(input.SoftwareID, input.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var existing models.SoftwareOrganizationMatch
 	if err := config.DB.Where("software_id = ?", input.SoftwareID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Match already exists"})
@@ -92,6 +97,8 @@ func CreateSoftwareOrganizationMatch(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param match body models.SoftwareOrganizationMatch true "Software Organization Match"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
 // @Success 201 {object} models.SoftwareOrganizationMatch
 // @Failure 400 {object} models.APIResponse
 // @Failure 409 {object} models.APIResponse
@@ -104,6 +111,11 @@ func CreateSoftwareOrganizationMatchWithAutoAssignment(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(input.SoftwareID, input.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Check for duplicates
 	var existing models.SoftwareOrganizationMatch
 	if err := config.DB.
@@ -113,16 +125,28 @@ func CreateSoftwareOrganizationMatchWithAutoAssignment(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Create(&input).Error; err != nil {
+	// Assign software to all staff
+	var staffList []models.Staff
+	if err := config.DB.Find(&staffList).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Assign software to all staff
-	var staffList []models.Staff
-	if err := config.DB.Find(&staffList).Error; err == nil {
-		utils.AutoAssignSoftwareToStaffByUnit(input.SoftwareID, staffList, utils.SourceOrganization)
+	if isDryRun(c) {
+		preview, err := utils.PreviewAutoAssign(input.SoftwareID, input.PlanID, staffList, utils.SourceOrganization)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Create(&input).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	utils.AutoAssignSoftwareToStaffByUnit(input.SoftwareID, input.PlanID, staffList, utils.SourceOrganization, input.ID)
 
 	c.JSON(http.StatusCreated, input)
 }
@@ -154,6 +178,11 @@ func UpdateSoftwareOrganizationMatch(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(match.SoftwareID, match.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := config.DB.Save(&match).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -186,6 +215,8 @@ func DeleteSoftwareOrganizationMatch(c *gin.Context) {
 // @Tags Software Organization Matches
 // @Produce json
 // @Param id path int true "Match ID"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
 // @Success 200  "No Content - Deletion Success"
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
@@ -199,16 +230,28 @@ func DeleteSoftwareOrganizationMatchAndRevokeAssignment(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&match).Error; err != nil {
+	// Revoke software from all staff (auto-assigned only)
+	var staffList []models.Staff
+	if err := config.DB.Find(&staffList).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Revoke software from all staff (auto-assigned only)
-	var staffList []models.Staff
-	if err := config.DB.Find(&staffList).Error; err == nil {
-		utils.AutoRevokeSoftwareFromStaff(match.SoftwareID, staffList, utils.SourceOrganization)
+	if isDryRun(c) {
+		preview, err := utils.PreviewAutoRevoke(match.SoftwareID, staffList, utils.SourceOrganization, match.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Delete(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	utils.AutoRevokeSoftwareFromStaff(match.SoftwareID, staffList, utils.SourceOrganization, match.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Match deleted and software revoked from staff"})
 }
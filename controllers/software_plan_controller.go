@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSoftwarePlans godoc
+// @Summary List the plans of a software
+// @Tags Software Plans
+// @Produce json
+// @Param id path int true "Software ID"
+// @Success 200 {array} models.SoftwarePlan
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/plans [get]
+func GetSoftwarePlans(c *gin.Context) {
+	var plans []models.SoftwarePlan
+	if err := config.DB.Where("software_id = ?", c.Param("id")).Order("tier ASC").Find(&plans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+// CreateSoftwarePlan godoc
+// @Summary Create a plan under a software
+// @Tags Software Plans
+// @Accept json
+// @Produce json
+// @Param id path int true "Software ID"
+// @Param plan body models.SoftwarePlan true "Plan object"
+// @Success 201 {object} models.SoftwarePlan
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software/{id}/plans [post]
+func CreateSoftwarePlan(c *gin.Context) {
+	var software models.Software
+	if err := config.DB.First(&software, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Software not found"})
+		return
+	}
+
+	var plan models.SoftwarePlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if plan.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plan name is required"})
+		return
+	}
+	plan.SoftwareID = software.ID
+	if plan.Currency == "" {
+		plan.Currency = software.Currency
+	}
+
+	// Check for duplicate plan name within the software
+	var existing models.SoftwarePlan
+	if err := config.DB.Where("software_id = ? AND name = ?", plan.SoftwareID, plan.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This software already has a plan with this name"})
+		return
+	}
+
+	if err := config.DB.Create(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// UpdateSoftwarePlan godoc
+// @Summary Update a software plan
+// @Tags Software Plans
+// @Accept json
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Param plan body models.SoftwarePlan true "Updated plan"
+// @Success 200 {object} models.SoftwarePlan
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-plans/{id} [put]
+func UpdateSoftwarePlan(c *gin.Context) {
+	id := c.Param("id")
+	var plan models.SoftwarePlan
+	if err := config.DB.First(&plan, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+		return
+	}
+
+	var input models.SoftwarePlan
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check for duplicate name within the software (excluding self)
+	var duplicate models.SoftwarePlan
+	if err := config.DB.Where("software_id = ? AND name = ? AND id != ?", plan.SoftwareID, input.Name, id).First(&duplicate).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Another plan of this software has this name"})
+		return
+	}
+
+	plan.Name = input.Name
+	plan.Tier = input.Tier
+	plan.SeatPrice = input.SeatPrice
+	plan.Features = input.Features
+	if input.BillingPeriod != "" {
+		plan.BillingPeriod = input.BillingPeriod
+	}
+	if input.Currency != "" {
+		plan.Currency = input.Currency
+	}
+
+	if err := config.DB.Save(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// DeleteSoftwarePlan godoc
+// @Summary Delete a software plan that is not in use
+// @Tags Software Plans
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-plans/{id} [delete]
+func DeleteSoftwarePlan(c *gin.Context) {
+	var plan models.SoftwarePlan
+	if err := config.DB.First(&plan, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+		return
+	}
+
+	// Refuse while assignments or matches still name the plan
+	for _, model := range []interface{}{
+		&models.AssignedSoftware{}, &models.SoftwareDepartmentMatch{},
+		&models.SoftwareTeamMatch{}, &models.SoftwareOrganizationMatch{},
+	} {
+		var count int64
+		if err := config.DB.Model(model).Where("plan_id = ?", plan.ID).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Plan is still used by assignments or matches"})
+			return
+		}
+	}
+
+	if err := config.DB.Delete(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plan deleted"})
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSoftwarePolicies godoc
+// @Summary List software conflict and requires policies
+// @Tags Software Policies
+// @Produce json
+// @Param software_id query int false "Filter by Software ID, on either side of the policy"
+// @Success 200 {array} models.SoftwarePolicy
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-policies [get]
+func GetSoftwarePolicies(c *gin.Context) {
+	var policies []models.SoftwarePolicy
+	query := config.DB.Preload("Software").Preload("RelatedSoftware")
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ? OR related_software_id = ?", sw, sw)
+	}
+	if err := query.Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// GetSoftwarePolicyByID godoc
+// @Summary Get a software policy
+// @Tags Software Policies
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} models.SoftwarePolicy
+// @Failure 404 {object} models.APIResponse
+// @Router /api/software-policies/{id} [get]
+func GetSoftwarePolicyByID(c *gin.Context) {
+	var policy models.SoftwarePolicy
+	if err := config.DB.Preload("Software").Preload("RelatedSoftware").First(&policy, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found"})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// CreateSoftwarePolicy godoc
+// @Summary Declare a software conflict or requires policy
+// @Description A conflict policy makes two software mutually exclusive; a requires policy only allows software_id for staff holding related_software_id. New assignments, manual or automatic, that would break a policy are refused. Existing assignments are left alone and show up in the violations report.
+// @Tags Software Policies
+// @Accept json
+// @Produce json
+// @Param policy body models.SoftwarePolicy true "Policy object"
+// @Success 201 {object} models.SoftwarePolicy
+// @Failure 400 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-policies [post]
+func CreateSoftwarePolicy(c *gin.Context) {
+	var policy models.SoftwarePolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateSoftwarePolicy(&policy); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if softwarePolicyExists(policy) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Policy already exists"})
+		return
+	}
+
+	if err := config.DB.Create(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdateSoftwarePolicy godoc
+// @Summary Update a software policy
+// @Tags Software Policies
+// @Accept json
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Param policy body models.SoftwarePolicy true "Updated policy"
+// @Success 200 {object} models.SoftwarePolicy
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-policies/{id} [put]
+func UpdateSoftwarePolicy(c *gin.Context) {
+	var policy models.SoftwarePolicy
+	if err := config.DB.First(&policy, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found"})
+		return
+	}
+	id := policy.ID
+
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	policy.ID = id
+	if msg := validateSoftwarePolicy(&policy); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if softwarePolicyExists(policy) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Policy already exists"})
+		return
+	}
+
+	if err := config.DB.Save(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteSoftwarePolicy godoc
+// @Summary Delete a software policy
+// @Tags Software Policies
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-policies/{id} [delete]
+func DeleteSoftwarePolicy(c *gin.Context) {
+	var policy models.SoftwarePolicy
+	if err := config.DB.First(&policy, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found"})
+		return
+	}
+	if err := config.DB.Delete(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete policy"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Policy deleted"})
+}
+
+// GetPolicyViolations godoc
+// @Summary List existing assignments that break a software policy
+// @Description Staff holding two conflicting software, or a software without the one it requires
+// @Tags Software Policies
+// @Produce json
+// @Success 200 {array} models.PolicyViolation
+// @Failure 500 {object} models.APIResponse
+// @Router /api/software-policies/violations [get]
+func GetPolicyViolations(c *gin.Context) {
+	violations, err := utils.FindPolicyViolations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, violations)
+}
+
+// validateSoftwarePolicy normalises the kind of a policy and returns a message describing what is
+// wrong with it, if anything
+func validateSoftwarePolicy(policy *models.SoftwarePolicy) string {
+	policy.Software = nil
+	policy.RelatedSoftware = nil
+	kind, ok := utils.NormalizePolicyKind(policy.Kind)
+	if !ok {
+		return "Kind must be conflict or requires"
+	}
+	policy.Kind = kind
+
+	var software models.Software
+	if err := config.DB.First(&software, policy.SoftwareID).Error; err != nil {
+		return "Software not found"
+	}
+	var related models.Software
+	if err := config.DB.First(&related, policy.RelatedSoftwareID).Error; err != nil {
+		return "Related software not found"
+	}
+	if policy.SoftwareID == policy.RelatedSoftwareID {
+		return "A policy needs two different software"
+	}
+	return ""
+}
+
+// softwarePolicyExists reports whether another policy of the same kind links the same software.
+// Conflicts are symmetric, so they match either way round.
+func softwarePolicyExists(policy models.SoftwarePolicy) bool {
+	query := config.DB.Model(&models.SoftwarePolicy{}).Where("id <> ? AND kind = ?", policy.ID, policy.Kind)
+	if policy.Kind == utils.PolicyConflict {
+		query = query.Where("(software_id = ? AND related_software_id = ?) OR (software_id = ? AND related_software_id = ?)",
+			policy.SoftwareID, policy.RelatedSoftwareID, policy.RelatedSoftwareID, policy.SoftwareID)
+	} else {
+		query = query.Where("software_id = ? AND related_software_id = ?", policy.SoftwareID, policy.RelatedSoftwareID)
+	}
+	var count int64
+	query.Count(&count)
+	return count > 0
+}
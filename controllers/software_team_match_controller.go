@@ -75,6 +75,11 @@ func CreateSoftwareTeamMatch(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(input.SoftwareID, input.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var existing models.SoftwareTeamMatch
 	if err := config.DB.Where("software_id = ? AND team_id = ?", input.SoftwareID, input.TeamID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Match already exists"})
@@ -96,6 +101,8 @@ func CreateSoftwareTeamMatch(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param match body models.SoftwareTeamMatch true "Software Team Match"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
 // @Success 201 {object} models.SoftwareTeamMatch
 // @Failure 400 {object} models.APIResponse
 // @Failure 409 {object} models.APIResponse
@@ -108,6 +115,11 @@ func CreateSoftwareTeamMatchWithAutoAssignment(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(match.SoftwareID, match.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Check for duplicates
 	var existing models.SoftwareTeamMatch
 	if err := config.DB.
@@ -117,16 +129,28 @@ func CreateSoftwareTeamMatchWithAutoAssignment(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Create(&match).Error; err != nil {
+	// Assign to all staff in team
+	var staffList []models.Staff
+	if err := config.DB.Where("team_id = ?", match.TeamID).Find(&staffList).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Assign to all staff in team
-	var staffList []models.Staff
-	if err := config.DB.Where("team_id = ?", match.TeamID).Find(&staffList).Error; err == nil {
-		utils.AutoAssignSoftwareToStaffByUnit(match.SoftwareID, staffList, utils.SourceTeam)
+	if isDryRun(c) {
+		preview, err := utils.PreviewAutoAssign(match.SoftwareID, match.PlanID, staffList, utils.SourceTeam)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Create(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	utils.AutoAssignSoftwareToStaffByUnit(match.SoftwareID, match.PlanID, staffList, utils.SourceTeam, match.ID)
 
 	c.JSON(http.StatusCreated, match)
 }
@@ -158,6 +182,11 @@ func UpdateSoftwareTeamMatch(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePlan(match.SoftwareID, match.PlanID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := config.DB.Save(&match).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -190,6 +219,8 @@ func DeleteSoftwareTeamMatch(c *gin.Context) {
 // @Tags Software Team Matches
 // @Produce json
 // @Param id path int true "Match ID"
+// @Param dry_run query bool false "Preview the per-staff changes without applying them"
+// @Success 200 {object} models.AssignmentPreview "Dry run preview"
 // @Success 200  "No Content - Deletion Success"
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
@@ -203,16 +234,28 @@ func DeleteSoftwareTeamMatchAndRevokeAssignments(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&match).Error; err != nil {
+	// Revoke software from staff in team
+	var staffList []models.Staff
+	if err := config.DB.Where("team_id = ?", match.TeamID).Find(&staffList).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Revoke software from staff in team
-	var staffList []models.Staff
-	if err := config.DB.Where("team_id = ?", match.TeamID).Find(&staffList).Error; err == nil {
-		utils.AutoRevokeSoftwareFromStaff(match.SoftwareID, staffList, utils.SourceTeam)
+	if isDryRun(c) {
+		preview, err := utils.PreviewAutoRevoke(match.SoftwareID, staffList, utils.SourceTeam, match.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if err := config.DB.Delete(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	utils.AutoRevokeSoftwareFromStaff(match.SoftwareID, staffList, utils.SourceTeam, match.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Match deleted and software revoked from team staff"})
 }
@@ -8,6 +8,7 @@ import (
 	"software_management/models"
 	"software_management/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -131,11 +132,12 @@ func CreateStaff(c *gin.Context) {
 
 // CreateStaffWithSoftwareMatch godoc
 // @Summary Create staff and auto-assign software
+// @Description When start_date lies in the future, the software is scheduled as pending assignments that activate on that date. The response includes the onboarding checklist recording what was provisioned, or onboarding_error when the onboarding could not be started.
 // @Tags Staff
 // @Accept json
 // @Produce json
 // @Param staff body models.Staff true "Staff object"
-// @Success 201 {object} models.Staff
+// @Success 201 {object} models.StaffWithOnboarding
 // @Failure 400 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/staff/with-software [post]
@@ -158,12 +160,18 @@ func CreateStaffWithSoftwareMatch(c *gin.Context) {
 		return
 	}
 
-	// Auto assign software after creation
-	if err := utils.AutoAssignSoftwareToStaff(staff.ID, staff.DepartmentID, staff.TeamID); err != nil {
-		log.Println("Auto-assignment error:", err)
+	// Auto assign software after creation, scheduled from the start date, and track it in the onboarding.
+	// The staff member is created either way; a failed onboarding is reported instead of returned.
+	response := models.StaffWithOnboarding{Staff: staff}
+	onboarding, err := utils.StartOnboarding(staff)
+	if err != nil {
+		log.Println("Onboarding error:", err)
+		response.OnboardingError = err.Error()
+	} else {
+		response.Onboarding = &onboarding
 	}
 
-	c.JSON(http.StatusCreated, staff)
+	c.JSON(http.StatusCreated, response)
 }
 
 // UpdateStaff godoc
@@ -236,6 +244,9 @@ func UpdateStaffWithSoftwareMatch(c *gin.Context) {
 	deptChanged := input.DepartmentID != oldDeptID
 	teamChanged := input.TeamID != oldTeamID
 	statusChanged := input.Status != existing.Status
+	startChanged := !sameDate(input.StartDate, existing.StartDate)
+	attributesChanged := statusChanged || input.Email != existing.Email || input.EmploymentType != existing.EmploymentType ||
+		input.FirstName != existing.FirstName || input.LastName != existing.LastName
 
 	// Apply updates
 	existing.FirstName = input.FirstName
@@ -244,12 +255,22 @@ func UpdateStaffWithSoftwareMatch(c *gin.Context) {
 	existing.DepartmentID = input.DepartmentID
 	existing.TeamID = input.TeamID
 	existing.Status = input.Status
+	existing.EmploymentType = input.EmploymentType
+	existing.StartDate = input.StartDate
+	existing.ManagerID = input.ManagerID
 
 	if err := config.DB.Save(&existing).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Move pending software to the new start date
+	if startChanged {
+		if err := utils.RescheduleStaffStart(existing.ID, existing.StartDate); err != nil {
+			log.Println("Failed to reschedule pending software:", err)
+		}
+	}
+
 	// Revoke software if staff is now inactive
 	if statusChanged && input.Status == "inactive" {
 		if err := utils.RevokeSoftwareAssignmentsForStaff(existing.ID); err != nil {
@@ -268,6 +289,11 @@ func UpdateStaffWithSoftwareMatch(c *gin.Context) {
 		); err != nil {
 			log.Println("Software sync error:", err)
 		}
+	} else if attributesChanged && input.Status != "inactive" {
+		// Attribute rules may match differently after other field changes
+		if err := utils.ReevaluateRulesForStaff(existing.ID, existing.DepartmentID, existing.TeamID); err != nil {
+			log.Println("Rule re-evaluation error:", err)
+		}
 	}
 
 	c.JSON(http.StatusOK, existing)
@@ -295,6 +321,10 @@ func DeleteStaff(c *gin.Context) {
 		log.Println("Failed to revoke software before delete:", err)
 	}
 
+	if err := utils.ReleaseOwnership(staff.ID); err != nil {
+		log.Println("Failed to release software ownership before delete:", err)
+	}
+
 	// Delete staff
 	if err := config.DB.Delete(&staff).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -324,8 +354,33 @@ func GetSoftwareAssignedToStaff(c *gin.Context) {
 	c.JSON(http.StatusOK, assignments)
 }
 
+// GetSoftwareOwnedByStaff godoc
+// @Summary Get the software a staff member owns or backs up as owner
+// @Tags Staff
+// @Produce json
+// @Param id path int true "Staff ID"
+// @Success 200 {array} models.OwnedSoftware
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/staff/{id}/owned-software [get]
+func GetSoftwareOwnedByStaff(c *gin.Context) {
+	var staff models.Staff
+	if err := config.DB.First(&staff, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Staff not found"})
+		return
+	}
+
+	owned, err := utils.FindOwnedSoftware(staff.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, owned)
+}
+
 // GetSoftwareAssignedToStaffWithDetails godoc
 // @Summary Get detailed software assigned to a specific staff using Software model
+// @Description The software the staff member is excluded from is listed by /api/staff/{id}/exclusions.
 // @Tags Staff
 // @Produce json
 // @Param id path int true "Staff ID"
@@ -433,3 +488,11 @@ func GetSoftwareNamesAssignedToStaff(c *gin.Context) {
 
 	c.JSON(http.StatusOK, names)
 }
+
+// sameDate reports whether two optional dates are equal
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportUsageEvents godoc
+// @Summary Bulk import usage events exported from vendor admin consoles
+// @Description Accepts JSONL (one JSON object per line) or CSV with a header row. Each event needs staff_email or staff_id, software or software_id, timestamp and optionally event_type. Matching assignments get their last_used_at moved forward.
+// @Tags Usage
+// @Accept plain
+// @Produce json
+// @Param format query string false "jsonl or csv (defaults from Content-Type)"
+// @Success 200 {object} models.UsageImportResult
+// @Failure 400 {object} models.APIResponse
+// @Router /api/usage-events/import [post]
+func ImportUsageEvents(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		switch contentType := c.ContentType(); {
+		case strings.Contains(contentType, "csv"):
+			format = utils.UsageFormatCSV
+		default:
+			format = utils.UsageFormatJSONL
+		}
+	}
+
+	records, lineErrors, err := utils.ParseUsageRecords(c.Request.Body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ImportUsageRecords(records, lineErrors))
+}
+
+// GetUsageEvents godoc
+// @Summary List imported usage events
+// @Tags Usage
+// @Produce json
+// @Param staff_id query int false "Filter by Staff ID"
+// @Param software_id query int false "Filter by Software ID"
+// @Param limit query int false "Limit results"
+// @Param offset query int false "Offset results"
+// @Success 200 {array} models.UsageEvent
+// @Failure 500 {object} models.APIResponse
+// @Router /api/usage-events [get]
+func GetUsageEvents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	var events []models.UsageEvent
+	query := config.DB.Model(&models.UsageEvent{})
+
+	if staff := c.Query("staff_id"); staff != "" {
+		query = query.Where("staff_id = ?", staff)
+	}
+	if sw := c.Query("software_id"); sw != "" {
+		query = query.Where("software_id = ?", sw)
+	}
+
+	if err := query.Order("occurred_at DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"net/http"
+
+	"software_management/config"
+	"software_management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVendors godoc
+// @Summary List all vendors
+// @Tags Vendors
+// @Produce json
+// @Param search query string false "Search by vendor name"
+// @Success 200 {array} models.Vendor
+// @Failure 500 {object} models.APIResponse
+// @Router /api/vendors [get]
+func GetVendors(c *gin.Context) {
+	var vendors []models.Vendor
+	query := config.DB.Model(&models.Vendor{})
+
+	if search := c.Query("search"); search != "" {
+		query = query.Where("name LIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Order("name ASC").Find(&vendors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, vendors)
+}
+
+// GetVendorByID godoc
+// @Summary Get a vendor by ID
+// @Tags Vendors
+// @Produce json
+// @Param id path int true "Vendor ID"
+// @Success 200 {object} models.Vendor
+// @Failure 404 {object} models.APIResponse
+// @Router /api/vendors/{id} [get]
+func GetVendorByID(c *gin.Context) {
+	id := c.Param("id")
+	var vendor models.Vendor
+	if err := config.DB.First(&vendor, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vendor not found"})
+		return
+	}
+	c.JSON(http.StatusOK, vendor)
+}
+
+// CreateVendor godoc
+// @Summary Create a new vendor
+// @Tags Vendors
+// @Accept json
+// @Produce json
+// @Param vendor body models.Vendor true "Vendor object"
+// @Success 201 {object} models.Vendor
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/vendors [post]
+func CreateVendor(c *gin.Context) {
+	var vendor models.Vendor
+	if err := c.ShouldBindJSON(&vendor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if vendor.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Vendor name is required"})
+		return
+	}
+
+	// Check for duplicate vendor name
+	var existing models.Vendor
+	if err := config.DB.Where("name = ?", vendor.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Vendor with this name already exists"})
+		return
+	}
+
+	if err := config.DB.Create(&vendor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, vendor)
+}
+
+// UpdateVendor godoc
+// @Summary Update a vendor by ID
+// @Tags Vendors
+// @Accept json
+// @Produce json
+// @Param id path int true "Vendor ID"
+// @Param vendor body models.Vendor true "Updated vendor"
+// @Success 200 {object} models.Vendor
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/vendors/{id} [put]
+func UpdateVendor(c *gin.Context) {
+	id := c.Param("id")
+	var vendor models.Vendor
+	if err := config.DB.First(&vendor, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vendor not found"})
+		return
+	}
+
+	var input models.Vendor
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check for duplicate name (excluding self)
+	var duplicate models.Vendor
+	if err := config.DB.Where("name = ? AND id != ?", input.Name, id).First(&duplicate).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Another vendor with this name already exists"})
+		return
+	}
+
+	vendor.Name = input.Name
+	vendor.Website = input.Website
+	vendor.ContactName = input.ContactName
+	vendor.ContactEmail = input.ContactEmail
+	vendor.Notes = input.Notes
+
+	if err := config.DB.Save(&vendor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, vendor)
+}
+
+// DeleteVendor godoc
+// @Summary Delete a vendor by ID (only if it has no contracts)
+// @Tags Vendors
+// @Produce json
+// @Param id path int true "Vendor ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/vendors/{id} [delete]
+func DeleteVendor(c *gin.Context) {
+	id := c.Param("id")
+	var vendor models.Vendor
+	if err := config.DB.First(&vendor, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vendor not found"})
+		return
+	}
+
+	var contracts int64
+	if err := config.DB.Model(&models.Contract{}).Where("vendor_id = ?", vendor.ID).Count(&contracts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if contracts > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vendor still has contracts; delete them first"})
+		return
+	}
+
+	if err := config.DB.Delete(&vendor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vendor deleted"})
+}
@@ -7,6 +7,7 @@ import (
 	_ "software_management/docs" // 👈 Required for Swagger docs
 	"software_management/models"
 	"software_management/routes"
+	"software_management/utils"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -16,7 +17,32 @@ import (
 
 func main() {
 	config.InitDB()
-	config.DB.AutoMigrate(&models.Department{})
+	config.DB.AutoMigrate(
+		&models.Department{}, &models.StaffPlain{}, &models.Software{}, &models.SoftwarePlan{}, &models.AssignedSoftware{}, &models.SoftwareAssignment{},
+		&models.AssignmentGrant{}, &models.SoftwareExclusion{},
+		&models.SoftwareBundle{}, &models.SoftwareBundleItem{}, &models.BundleAssignment{}, &models.SoftwareBundleMatch{}, &models.SoftwarePolicy{}, &models.AccessRequest{},
+		&models.AccessReviewCampaign{}, &models.AccessReviewItem{},
+		&models.Onboarding{}, &models.OnboardingItem{},
+		&models.Offboarding{}, &models.OffboardingItem{},
+		&models.SoftwareDepartmentMatch{}, &models.SoftwareTeamMatch{}, &models.SoftwareOrganizationMatch{},
+		&models.SoftwareAttributeRule{},
+		&models.Vendor{}, &models.Contract{}, &models.Reminder{},
+		&models.LicenseKey{}, &models.LicenseKeyAccessLog{}, &models.UsageEvent{},
+		&models.ReclamationPolicy{},
+	)
+
+	// Record grants for assignments made before grant sources were tracked
+	if err := utils.BackfillAssignmentGrants(); err != nil {
+		log.Println("Failed to backfill assignment grants:", err)
+	}
+
+	// Background jobs
+	utils.RunEvery("contract-reminders", utils.SchedulerInterval(), utils.ProcessContractReminders)
+	utils.RunEvery("seat-reclamation", utils.SchedulerInterval(), utils.ProcessSeatReclamation)
+	utils.RunEvery("assignment-activation", utils.SchedulerInterval(), utils.ProcessPendingActivations)
+	utils.RunEvery("assignment-expiry", utils.SchedulerInterval(), utils.ProcessAssignmentExpiry)
+	utils.RunEvery("assignment-reconciliation", utils.SchedulerInterval(), utils.ReconcileAssignments)
+	utils.RunEvery("offboarding", utils.SchedulerInterval(), utils.ProcessDueOffboardings)
 
 	r := routes.RegisterRoutes()
 
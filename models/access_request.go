@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// AccessRequest is a staff member asking for a software. It stays pending until an approver approves
+// it, which assigns the software, or rejects it, or the requester cancels it. Requests are history:
+// like assignment logs they outlive the staff and software they name.
+// swagger:model
+type AccessRequest struct {
+	ID                 uint        `gorm:"primaryKey" json:"id" example:"1"`
+	StaffID            uint        `gorm:"index;not null" json:"staff_id" example:"2"` // The requester
+	Staff              *StaffPlain `gorm:"foreignKey:StaffID;constraint:-" json:"staff,omitempty"`
+	SoftwareID         uint        `gorm:"index;not null" json:"software_id" example:"3"`
+	Software           *Software   `gorm:"foreignKey:SoftwareID;constraint:-" json:"software,omitempty"`
+	PlanID             *uint       `json:"plan_id" example:"1"`
+	Justification      string      `gorm:"type:text;not null" json:"justification" example:"Need Figma to review the new onboarding designs"`
+	Status             string      `gorm:"size:20;not null;default:'pending';index" json:"status" example:"pending"` // "pending", "approved", "rejected", "cancelled"
+	ApproverID         *uint       `gorm:"index" json:"approver_id" example:"5"`                                     // Staff member who approved or rejected it
+	Approver           *StaffPlain `gorm:"foreignKey:ApproverID;constraint:-" json:"approver,omitempty"`
+	DecisionNote       string      `gorm:"type:text" json:"decision_note" example:"Approved for the Q3 redesign"`
+	DecidedAt          *time.Time  `json:"decided_at" example:"2025-06-12T09:30:00Z"`
+	AssignedSoftwareID *uint       `json:"assigned_software_id" example:"42"` // The assignment an approval created or granted
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+func (AccessRequest) TableName() string {
+	return "access_requests"
+}
+
+// AccessRequestDecision is the body of an approve or reject call.
+// swagger:model
+type AccessRequestDecision struct {
+	ApproverID uint   `json:"approver_id" binding:"required" example:"5"`
+	Note       string `json:"note" example:"Approved for the Q3 redesign"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// AccessReviewCampaign certifies that staff still need their access, e.g. every quarter for ISO 27001.
+// Opening a campaign snapshots the active assignments in its scope into review items.
+// swagger:model
+type AccessReviewCampaign struct {
+	ID          uint               `gorm:"primaryKey" json:"id" example:"1"`
+	Name        string             `gorm:"not null" json:"name" example:"Q3 2025 access review"`
+	ScopeType   string             `gorm:"size:20;not null" json:"scope_type" example:"Department"`              // "Organization", "Department", "Software"
+	ScopeID     uint               `gorm:"not null;default:0" json:"scope_id" example:"2"`                       // 0 for the organization
+	Reviewer    string             `gorm:"size:20;not null;default:'manager'" json:"reviewer" example:"manager"` // Who reviews each item first: "manager" or "owner" of the software
+	Status      string             `gorm:"size:20;not null;default:'open';index" json:"status" example:"open"`   // "open", "completed"
+	DueDate     *time.Time         `json:"due_date" example:"2025-09-30T00:00:00Z"`
+	CompletedAt *time.Time         `json:"completed_at"`
+	Items       []AccessReviewItem `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+func (AccessReviewCampaign) TableName() string {
+	return "access_review_campaigns"
+}
+
+// AccessReviewItem is one assignment under review, as it was when the campaign opened, and the
+// reviewer's keep or revoke decision on it.
+// swagger:model
+type AccessReviewItem struct {
+	ID                 uint        `gorm:"primaryKey" json:"id" example:"1"`
+	CampaignID         uint        `gorm:"index;not null" json:"campaign_id" example:"1"`
+	AssignedSoftwareID uint        `gorm:"index;not null" json:"assigned_software_id" example:"42"`
+	StaffID            uint        `gorm:"index;not null" json:"staff_id" example:"2"`
+	Staff              *StaffPlain `gorm:"foreignKey:StaffID;constraint:OnDelete:CASCADE" json:"staff,omitempty"`
+	SoftwareID         uint        `gorm:"index;not null" json:"software_id" example:"3"`
+	Software           *Software   `gorm:"foreignKey:SoftwareID;constraint:OnDelete:CASCADE" json:"software,omitempty"`
+	PlanID             *uint       `json:"plan_id" example:"1"`
+	Source             string      `json:"source" example:"department"`
+	ReviewerID         *uint       `gorm:"index" json:"reviewer_id" example:"4"`   // Nil when the staff member has no manager and the software no owner
+	Decision           string      `gorm:"size:20" json:"decision" example:"keep"` // "" while pending, "keep" or "revoke"
+	Note               string      `gorm:"type:text" json:"note" example:"Still maintains the billing service"`
+	DecidedAt          *time.Time  `json:"decided_at"`
+	ExclusionID        *uint       `json:"exclusion_id" example:"7"` // Exclusion a revoke left so that matches and rules do not give the software back
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+func (AccessReviewItem) TableName() string {
+	return "access_review_items"
+}
+
+// AccessReviewDecision is the body of a keep or revoke decision on a review item.
+// swagger:model
+type AccessReviewDecision struct {
+	ReviewerID uint   `json:"reviewer_id" binding:"required" example:"4"`
+	Decision   string `json:"decision" binding:"required" example:"revoke"` // "keep", "revoke"
+	Note       string `json:"note" example:"Moved to the data team, no longer needs it"`
+}
+
+// ReviewerProgress is how far one reviewer got through their items of a campaign.
+type ReviewerProgress struct {
+	ReviewerID uint   `json:"reviewer_id" example:"4"`
+	Email      string `json:"email" example:"jane.roe@shuttlers.co"`
+	Total      int    `json:"total" example:"12"`
+	Decided    int    `json:"decided" example:"9"`
+}
+
+// AccessReviewReport summarises the decisions of a campaign.
+// swagger:model
+type AccessReviewReport struct {
+	CampaignID  uint                `json:"campaign_id" example:"1"`
+	Name        string              `json:"name" example:"Q3 2025 access review"`
+	Status      string              `json:"status" example:"completed"`
+	Total       int                 `json:"total" example:"120"`
+	Kept        int                 `json:"kept" example:"104"`
+	Revoked     int                 `json:"revoked" example:"11"`
+	Pending     int                 `json:"pending" example:"5"`
+	Unassigned  int                 `json:"unassigned" example:"0"`     // Pending items without a reviewer
+	Completion  float64             `json:"completion" example:"95.83"` // Percentage of items decided
+	Reviewers   []ReviewerProgress  `json:"reviewers"`
+	Exclusions  []SoftwareExclusion `json:"exclusions"` // Standing exclusions left by revokes; lifted through the software exclusions API
+	CompletedAt *time.Time          `json:"completed_at"`
+}
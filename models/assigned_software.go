@@ -5,12 +5,18 @@ import "time"
 // AssignedSoftware represents the relationship between a staff and an assigned software.
 // swagger:model
 type AssignedSoftware struct {
-	ID         uint      `json:"id" gorm:"primaryKey" example:"1"`
-	StaffID    uint      `json:"staff_id" gorm:"index;not null" example:"2"`
-	SoftwareID uint      `json:"software_id" gorm:"index;not null" example:"3"`
-	Source     string    `json:"source" gorm:"type:enum('manual','organization','department','team');default:'manual'" example:"department"`
-	AssignedAt time.Time `json:"assigned_at" gorm:"column:assigned_at;autoCreateTime"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	ID         uint              `json:"id" gorm:"primaryKey" example:"1"`
+	StaffID    uint              `json:"staff_id" gorm:"index;not null" example:"2"`
+	SoftwareID uint              `json:"software_id" gorm:"index;not null" example:"3"`
+	PlanID     *uint             `json:"plan_id" gorm:"index" example:"1"`
+	Source     string            `json:"source" gorm:"type:enum('manual','organization','department','team','rule','bundle');default:'manual'" example:"department"`
+	AssignedAt time.Time         `json:"assigned_at" gorm:"column:assigned_at;autoCreateTime"`
+	Status     string            `json:"status" gorm:"type:enum('pending','active');default:'active';index" example:"active"` // pending until StartsAt, then active
+	StartsAt   *time.Time        `json:"starts_at" gorm:"column:starts_at"`                                                   // Activation date of a future-dated assignment
+	LastUsedAt *time.Time        `json:"last_used_at" gorm:"column:last_used_at"`                                             // Last usage seen in vendor usage events
+	ExpiresAt  *time.Time        `json:"expires_at" gorm:"column:expires_at;index"`                                           // End of a time-bound manual assignment
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	Grants     []AssignmentGrant `json:"grants,omitempty" gorm:"foreignKey:AssignedSoftwareID;constraint:OnDelete:CASCADE"` // Every match, rule or manual assignment behind this assignment
 }
 
 func (AssignedSoftware) TableName() string {
@@ -23,12 +29,24 @@ type AssignedSoftwareDetail struct {
 	ID         uint      `json:"id" example:"1"`
 	StaffID    uint      `json:"staff_id" example:"2"`
 	SoftwareID uint      `json:"software_id" example:"3"`
-	Software   string    `json:"software" example:"ClickUp"`                                                                                 // <-- this is software.name
-	Source     string    `json:"source" gorm:"type:enum('manual','organization','department','team');default:'manual'" example:"department"` // "manual", "organization", "department", "team"
+	Software   string    `json:"software" example:"ClickUp"`                                                                                                 // <-- this is software.name
+	Source     string    `json:"source" gorm:"type:enum('manual','organization','department','team','rule','bundle');default:'manual'" example:"department"` // "manual", "organization", "department", "team", "rule", "bundle"
 	AssignedAt time.Time `json:"assigned_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// ExpiringAssignment is a time-bound assignment that expires within a queried window.
+// swagger:model
+type ExpiringAssignment struct {
+	AssignmentID uint      `json:"assignment_id" example:"12"`
+	StaffID      uint      `json:"staff_id" example:"2"`
+	StaffEmail   string    `json:"staff_email" example:"john.doe@shuttlers.co"`
+	SoftwareID   uint      `json:"software_id" example:"3"`
+	Software     string    `json:"software" example:"Figma"`
+	ExpiresAt    time.Time `json:"expires_at" example:"2025-07-31T00:00:00Z"`
+	DaysLeft     int       `json:"days_left" example:"5"`
+}
+
 /**
 type AssignedSoftware struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
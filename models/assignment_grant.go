@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AssignmentGrant is one reason a staff member holds an assigned software: a manual assignment or a
+// specific match or rule. An assignment is only revoked when its last grant disappears.
+// swagger:model
+type AssignmentGrant struct {
+	ID                 uint      `gorm:"primaryKey" json:"id" example:"1"`
+	AssignedSoftwareID uint      `gorm:"uniqueIndex:idx_assignment_grant;not null" json:"assigned_software_id" example:"12"`
+	SourceType         string    `gorm:"uniqueIndex:idx_assignment_grant;size:32;not null" json:"source_type" example:"team"` // manual | organization | department | team | rule | attribute_rule | bundle | bundle_match
+	SourceID           uint      `gorm:"uniqueIndex:idx_assignment_grant;not null;default:0" json:"source_id" example:"4"`    // ID of the match, rule or bundle assignment; 0 for manual grants and grants recorded before sources were tracked
+	PlanID             *uint     `json:"plan_id" example:"1"`
+	BundleID           *uint     `json:"bundle_id,omitempty" example:"1"` // Bundle the software was granted as part of
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (AssignmentGrant) TableName() string {
+	return "assignment_grants"
+}
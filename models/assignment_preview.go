@@ -0,0 +1,27 @@
+package models
+
+// AssignmentChange is what a match or rule change would do to one staff member's assignment.
+type AssignmentChange struct {
+	StaffID    uint   `json:"staff_id" example:"2"`
+	StaffEmail string `json:"staff_email" example:"john.doe@shuttlers.co"`
+	SoftwareID uint   `json:"software_id" example:"3"`
+	Action     string `json:"action" example:"assign"` // assign | upgrade | revoke | skip
+	PlanID     *uint  `json:"plan_id,omitempty" example:"1"`
+	Reason     string `json:"reason,omitempty" example:"no seats available: the license pool for this software is full"`
+}
+
+// AssignmentPreview is the per-staff diff of a match or rule change, worked out without writing anything.
+// swagger:model
+type AssignmentPreview struct {
+	DryRun     bool               `json:"dry_run" example:"true"`
+	SoftwareID uint               `json:"software_id" example:"3"`
+	BundleID   *uint              `json:"bundle_id,omitempty" example:"1"` // Set when previewing a bundle, whose changes span several software
+	Software   string             `json:"software" example:"Slack"`
+	Source     string             `json:"source" example:"organization"`
+	Assign     int                `json:"assign" example:"40"`
+	Upgrade    int                `json:"upgrade" example:"2"`
+	Revoke     int                `json:"revoke" example:"0"`
+	Skip       int                `json:"skip" example:"3"`
+	Unchanged  int                `json:"unchanged" example:"12"`
+	Changes    []AssignmentChange `json:"changes"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Contract represents a purchase agreement with a vendor for a software.
+// swagger:model
+type Contract struct {
+	ID               uint      `gorm:"primaryKey" json:"id" example:"1"`
+	VendorID         uint      `gorm:"index;not null" json:"vendor_id" example:"1"`
+	Vendor           Vendor    `gorm:"foreignKey:VendorID" json:"vendor"`
+	SoftwareID       uint      `gorm:"index;not null" json:"software_id" example:"2"`
+	Software         Software  `gorm:"foreignKey:SoftwareID" json:"software"`
+	ContractNumber   string    `gorm:"unique;not null" json:"contract_number" example:"CU-2025-001"`
+	StartDate        time.Time `json:"start_date" example:"2025-01-01T00:00:00Z"`
+	EndDate          time.Time `gorm:"index" json:"end_date" example:"2025-12-31T00:00:00Z"`
+	AutoRenew        bool      `json:"auto_renew" example:"true"`
+	NoticePeriodDays int       `json:"notice_period_days" example:"30"`
+	SeatCount        int       `json:"seat_count" example:"50"`
+	Value            float64   `json:"value" example:"6000"`
+	Currency         string    `gorm:"default:'USD'" json:"currency" example:"USD"`
+	Notes            string    `json:"notes" example:"Annual prepaid"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (Contract) TableName() string {
+	return "contracts"
+}
+
+// NoticeDeadline is the last day a cancellation or change can be sent to the vendor.
+func (c Contract) NoticeDeadline() time.Time {
+	return c.EndDate.AddDate(0, 0, -c.NoticePeriodDays)
+}
+
+// ContractRenewal is a contract that ends or hits its notice deadline within a queried window.
+// swagger:model
+type ContractRenewal struct {
+	Contract
+	NoticeDeadline  time.Time `json:"notice_deadline" example:"2025-12-01T00:00:00Z"`
+	DaysUntilNotice int       `json:"days_until_notice" example:"12"`
+	DaysUntilEnd    int       `json:"days_until_end" example:"42"`
+}
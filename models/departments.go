@@ -5,10 +5,13 @@ import "time"
 // Department represents a department in the organization.
 // swagger:model
 type Department struct {
-	ID        uint      `gorm:"primaryKey" json:"id" example:"1"`
-	Name      string    `gorm:"unique;not null" json:"name" example:"Engineering"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Name              string    `gorm:"unique;not null" json:"name" example:"Engineering"`
+	AnnualBudget      float64   `gorm:"default:0" json:"annual_budget" example:"50000"` // Annual software budget, 0 means no budget
+	BudgetCurrency    string    `gorm:"default:'USD'" json:"budget_currency" example:"USD"`
+	BudgetEnforcement string    `gorm:"default:'warn'" json:"budget_enforcement" example:"warn"` // warn | reject
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 func (Department) TableName() string {
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// DriftItem is one difference between the software a staff member should hold and what assigned_software says.
+type DriftItem struct {
+	Kind         string `json:"kind" example:"missing"` // missing | stale | inactive_staff | missing_grant | stale_grant
+	StaffID      uint   `json:"staff_id" example:"2"`
+	StaffEmail   string `json:"staff_email" example:"john.doe@shuttlers.co"`
+	SoftwareID   uint   `json:"software_id" example:"3"`
+	Software     string `json:"software" example:"Slack"`
+	Source       string `json:"source" example:"team"`                        // Source that grants (missing) or granted (stale) the software
+	SourceID     uint   `json:"source_id,omitempty" example:"4"`              // Match or rule behind a missing or stale grant
+	AssignmentID *uint  `json:"assignment_id,omitempty" example:"12"`         // Empty for missing assignments
+	PlanID       *uint  `json:"plan_id,omitempty" example:"1"`                // Plan a missing assignment would get
+	Fixed        bool   `json:"fixed" example:"false"`                        // Whether apply mode corrected it
+	Error        string `json:"error,omitempty" example:"no seats available"` // Why apply mode could not correct it
+}
+
+// DriftReport compares every staff member's desired software with assigned_software.
+// swagger:model
+type DriftReport struct {
+	GeneratedAt   time.Time   `json:"generated_at"`
+	Applied       bool        `json:"applied" example:"false"`
+	StaffChecked  int         `json:"staff_checked" example:"120"`
+	Missing       int         `json:"missing" example:"4"`
+	Stale         int         `json:"stale" example:"2"`
+	InactiveStaff int         `json:"inactive_staff" example:"1"`
+	MissingGrants int         `json:"missing_grants" example:"3"`
+	StaleGrants   int         `json:"stale_grants" example:"1"`
+	Fixed         int         `json:"fixed" example:"0"`
+	Items         []DriftItem `json:"items"`
+}
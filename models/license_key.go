@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// LicenseKey is a license key for a key-based software, stored encrypted at rest.
+// swagger:model
+type LicenseKey struct {
+	ID                 uint       `gorm:"primaryKey" json:"id" example:"1"`
+	SoftwareID         uint       `gorm:"index;not null" json:"software_id" example:"4"`
+	Label              string     `json:"label" example:"Office 2021 volume key #3"`
+	EncryptedKey       string     `gorm:"type:text;not null" json:"-"`
+	KeyHint            string     `json:"key_hint" example:"****-7QX9"` // Last characters of the key, safe to display
+	AssignedSoftwareID *uint      `gorm:"index" json:"assigned_software_id" example:"12"`
+	AllocatedAt        *time.Time `json:"allocated_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func (LicenseKey) TableName() string {
+	return "license_keys"
+}
+
+// LicenseKeyAccessLog is an audit record of a license key being revealed in plaintext.
+// swagger:model
+type LicenseKeyAccessLog struct {
+	ID           uint      `gorm:"primaryKey" json:"id" example:"1"`
+	LicenseKeyID uint      `gorm:"index;not null" json:"license_key_id" example:"1"`
+	SoftwareID   uint      `json:"software_id" example:"4"`
+	RequestedBy  uint      `json:"requested_by" example:"2"`
+	Reason       string    `json:"reason" example:"Reinstalling Office on replacement laptop"`
+	ClientIP     string    `json:"client_ip" example:"10.0.0.12"`
+	AccessedAt   time.Time `gorm:"index" json:"accessed_at"`
+}
+
+func (LicenseKeyAccessLog) TableName() string {
+	return "license_key_access_logs"
+}
@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Offboarding is a staff member's departure, scheduled for their last day. On that date every software
+// they hold is deprovisioned: software granted by matches and rules is revoked, and manually assigned
+// software is revoked, transferred to another staff member or kept with a reason, as planned per item.
+// swagger:model
+type Offboarding struct {
+	ID             uint              `gorm:"primaryKey" json:"id" example:"1"`
+	StaffID        uint              `gorm:"index;not null" json:"staff_id" example:"2"`
+	Staff          *StaffPlain       `gorm:"foreignKey:StaffID;constraint:OnDelete:CASCADE" json:"staff,omitempty"`
+	EffectiveDate  time.Time         `gorm:"not null;index" json:"effective_date" example:"2025-07-31T17:00:00Z"`          // Last day; software is deprovisioned then
+	ManualHandling string            `gorm:"size:20;not null;default:'revoke'" json:"manual_handling" example:"revoke"`    // Default for manual assignments: "revoke", "transfer", "keep"
+	TransferToID   *uint             `json:"transfer_to_id" example:"4"`                                                   // Default recipient of transferred software
+	Reason         string            `gorm:"type:text" json:"reason" example:"Kept until the handover of the Q3 accounts"` // Default reason for kept software
+	Status         string            `gorm:"size:20;not null;default:'scheduled';index" json:"status" example:"scheduled"` // "scheduled", "in_progress", "completed", "cancelled"
+	Forced         bool              `gorm:"not null" json:"forced" example:"false"`                                       // Runs even when it leaves software without an active owner
+	CompletedAt    *time.Time        `json:"completed_at"`
+	Items          []OffboardingItem `gorm:"foreignKey:OffboardingID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+func (Offboarding) TableName() string {
+	return "offboardings"
+}
+
+// OffboardingItem is the deprovisioning of one software the leaver holds: what is planned for it and
+// what happened to it. The software's name is kept so that the certificate outlives the software.
+// swagger:model
+type OffboardingItem struct {
+	ID                 uint       `gorm:"primaryKey" json:"id" example:"1"`
+	OffboardingID      uint       `gorm:"index;not null" json:"offboarding_id" example:"1"`
+	AssignedSoftwareID uint       `gorm:"index;not null" json:"assigned_software_id" example:"12"`
+	SoftwareID         uint       `gorm:"index;not null" json:"software_id" example:"3"`
+	Software           string     `gorm:"not null" json:"software" example:"Figma"`
+	Source             string     `gorm:"size:20" json:"source" example:"manual"`            // "manual" when a manual assignment is behind it, else "auto"
+	Action             string     `gorm:"size:20;not null" json:"action" example:"transfer"` // "revoke", "transfer", "keep"
+	TransferToID       *uint      `json:"transfer_to_id" example:"4"`
+	Reason             string     `gorm:"type:text" json:"reason" example:"Needed to close the Q3 accounts"`
+	Status             string     `gorm:"size:20;not null;default:'pending'" json:"status" example:"transferred"` // "pending", "revoked", "transferred", "kept", "failed"
+	Detail             string     `gorm:"type:text" json:"detail,omitempty" example:"no seats available: the license pool for this software is full"`
+	ProcessedAt        *time.Time `json:"processed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func (OffboardingItem) TableName() string {
+	return "offboarding_items"
+}
+
+// OffboardingPlan is the body of scheduling an offboarding: the last day, how manually assigned
+// software is handled by default, and optional per-software exceptions.
+// swagger:model
+type OffboardingPlan struct {
+	EffectiveDate  *time.Time          `json:"effective_date" example:"2025-07-31T17:00:00Z"` // Defaults to now, which offboards right away
+	ManualHandling string              `json:"manual_handling" example:"revoke"`              // "revoke" (default), "transfer", "keep"
+	TransferToID   *uint               `json:"transfer_to_id" example:"4"`
+	Reason         string              `json:"reason" example:"Kept until the handover of the Q3 accounts"`
+	Overrides      []OffboardingAction `json:"overrides"`
+}
+
+// OffboardingAction is what to do with one manually assigned software of a leaver.
+// swagger:model
+type OffboardingAction struct {
+	SoftwareID   uint   `json:"software_id" example:"3"`
+	Action       string `json:"action" binding:"required" example:"keep"` // "revoke", "transfer", "keep"
+	TransferToID *uint  `json:"transfer_to_id" example:"4"`
+	Reason       string `json:"reason" example:"Needed to close the Q3 accounts"`
+}
+
+// OffboardingCertificate is the final record of a completed offboarding: everything removed from,
+// transferred away from or left with the leaver, with the time each was processed.
+// swagger:model
+type OffboardingCertificate struct {
+	OffboardingID uint                          `json:"offboarding_id" example:"1"`
+	StaffID       uint                          `json:"staff_id" example:"2"`
+	StaffName     string                        `json:"staff_name" example:"John Doe"`
+	StaffEmail    string                        `json:"staff_email" example:"john.doe@shuttlers.co"`
+	EffectiveDate time.Time                     `json:"effective_date" example:"2025-07-31T17:00:00Z"`
+	CompletedAt   time.Time                     `json:"completed_at" example:"2025-07-31T17:05:00Z"`
+	Revoked       []OffboardingCertificateEntry `json:"revoked"`
+	Transferred   []OffboardingCertificateEntry `json:"transferred"`
+	Kept          []OffboardingCertificateEntry `json:"kept"`
+	IssuedAt      time.Time                     `json:"issued_at" example:"2025-08-01T09:00:00Z"`
+}
+
+// OffboardingCertificateEntry is one software on an offboarding certificate.
+// swagger:model
+type OffboardingCertificateEntry struct {
+	SoftwareID      uint      `json:"software_id" example:"3"`
+	Software        string    `json:"software" example:"Figma"`
+	Source          string    `json:"source" example:"manual"`
+	TransferToID    *uint     `json:"transfer_to_id,omitempty" example:"4"`
+	TransferToEmail string    `json:"transfer_to_email,omitempty" example:"jane.roe@shuttlers.co"`
+	Reason          string    `json:"reason,omitempty" example:"Needed to close the Q3 accounts"`
+	Detail          string    `json:"detail,omitempty"`
+	ProcessedAt     time.Time `json:"processed_at" example:"2025-07-31T17:05:00Z"`
+}
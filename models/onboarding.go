@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Onboarding tracks what a new staff member should get: every software their matches and rules
+// entitle them to, and manual checklist tasks such as issuing a laptop. It closes once every item
+// is provisioned, done or skipped.
+// swagger:model
+type Onboarding struct {
+	ID          uint             `gorm:"primaryKey" json:"id" example:"1"`
+	StaffID     uint             `gorm:"uniqueIndex;not null" json:"staff_id" example:"2"`
+	Staff       *StaffPlain      `gorm:"foreignKey:StaffID;constraint:OnDelete:CASCADE" json:"staff,omitempty"`
+	Status      string           `gorm:"size:20;not null;default:'open';index" json:"status" example:"open"` // "open", "completed"
+	CompletedAt *time.Time       `json:"completed_at"`
+	Items       []OnboardingItem `gorm:"foreignKey:OnboardingID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func (Onboarding) TableName() string {
+	return "onboardings"
+}
+
+// OnboardingItem is one expected software or one manual task of an onboarding.
+// Software items are pending, provisioned, failed or skipped; tasks are pending, done or skipped.
+// swagger:model
+type OnboardingItem struct {
+	ID           uint       `gorm:"primaryKey" json:"id" example:"1"`
+	OnboardingID uint       `gorm:"index;not null" json:"onboarding_id" example:"1"`
+	Kind         string     `gorm:"size:20;not null" json:"kind" example:"software"` // "software", "task"
+	SoftwareID   *uint      `json:"software_id,omitempty" example:"3"`
+	Title        string     `gorm:"not null" json:"title" example:"Slack"`
+	Status       string     `gorm:"size:20;not null;default:'pending'" json:"status" example:"provisioned"`
+	Detail       string     `gorm:"type:text" json:"detail,omitempty" example:"no seats available: the license pool for this software is full"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (OnboardingItem) TableName() string {
+	return "onboarding_items"
+}
+
+// StaffWithOnboarding is a newly created staff member together with their onboarding checklist, or
+// the reason it could not be started.
+// swagger:model
+type StaffWithOnboarding struct {
+	Staff
+	Onboarding      *Onboarding `json:"onboarding,omitempty"`
+	OnboardingError string      `json:"onboarding_error,omitempty"` // Why the onboarding could not be started
+}
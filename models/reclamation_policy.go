@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ReclamationPolicy revokes seats of a software that have been idle for IdleDays,
+// after warning the holder WarningDays beforehand.
+// swagger:model
+type ReclamationPolicy struct {
+	ID          uint      `gorm:"primaryKey" json:"id" example:"1"`
+	SoftwareID  uint      `gorm:"uniqueIndex;not null" json:"software_id" example:"3"`
+	Software    *Software `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`
+	IdleDays    int       `gorm:"not null;default:60" json:"idle_days" example:"60"`
+	WarningDays int       `gorm:"not null;default:7" json:"warning_days" example:"7"`
+	Enabled     bool      `gorm:"not null" json:"enabled" example:"true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (ReclamationPolicy) TableName() string {
+	return "reclamation_policies"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Reminder records a reminder event that has fired, so each one fires only once.
+// swagger:model
+type Reminder struct {
+	ID           uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Kind         string    `gorm:"index;not null" json:"kind" example:"contract_notice_deadline"` // contract_notice_deadline | contract_end | idle_seat_warning | budget_overspend | assignment_expiry
+	DedupKey     string    `gorm:"unique;not null" json:"-"`
+	ContractID   *uint     `gorm:"index" json:"contract_id,omitempty" example:"4"`
+	Contract     *Contract `gorm:"foreignKey:ContractID" json:"contract,omitempty"`
+	DepartmentID *uint     `gorm:"index" json:"department_id,omitempty" example:"2"`
+	DueDate      time.Time `json:"due_date" example:"2025-12-01T00:00:00Z"`
+	Message      string    `json:"message" example:"Notice deadline for contract CU-2025-001 (ClickUp) is in 14 days"`
+	FiredAt      time.Time `gorm:"index" json:"fired_at" example:"2025-11-17T08:00:00Z"`
+}
+
+func (Reminder) TableName() string {
+	return "reminders"
+}
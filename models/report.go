@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// SpendRow is a raw aggregate of seats held by one team for one software.
+type SpendRow struct {
+	DepartmentID  uint    `json:"department_id"`
+	Department    string  `json:"department"`
+	TeamID        uint    `json:"team_id"`
+	Team          string  `json:"team"`
+	SoftwareID    uint    `json:"software_id"`
+	Software      string  `json:"software"`
+	SeatPrice     float64 `json:"seat_price"`
+	BillingPeriod string  `json:"billing_period"`
+	Currency      string  `json:"currency"`
+	Seats         int     `json:"seats"`
+}
+
+// SpendLine is the monthly and annual cost of one department, team or software.
+// swagger:model
+type SpendLine struct {
+	ID          uint    `json:"id" example:"1"`
+	Name        string  `json:"name" example:"Engineering"`
+	Currency    string  `json:"currency" example:"USD"`
+	Seats       int     `json:"seats" example:"24"`
+	MonthlyCost float64 `json:"monthly_cost" example:"300"`
+	AnnualCost  float64 `json:"annual_cost" example:"3600"`
+}
+
+// SpendReport breaks down current software spend by department, team and software.
+// swagger:model
+type SpendReport struct {
+	ByDepartment []SpendLine `json:"by_department"`
+	ByTeam       []SpendLine `json:"by_team"`
+	BySoftware   []SpendLine `json:"by_software"`
+}
+
+// ChargebackLine is one department's share of one software's cost for a billing period.
+// swagger:model
+type ChargebackLine struct {
+	Period       string  `json:"period" example:"2025-06"`
+	DepartmentID uint    `json:"department_id" example:"1"`
+	Department   string  `json:"department" example:"Engineering"`
+	SoftwareID   uint    `json:"software_id" example:"2"`
+	Software     string  `json:"software" example:"ClickUp"`
+	Currency     string  `json:"currency" example:"USD"`
+	SeatDays     float64 `json:"seat_days" example:"270"`
+	AverageSeats float64 `json:"average_seats" example:"9"`
+	Share        float64 `json:"share" example:"0.75"` // Fraction of the software's seat-days held by the department
+	Cost         float64 `json:"cost" example:"112.5"`
+}
+
+// IdleSeat is an assignment with no recorded usage for at least the idle threshold.
+// swagger:model
+type IdleSeat struct {
+	AssignmentID uint       `json:"assignment_id" example:"12"`
+	StaffID      uint       `json:"staff_id" example:"2"`
+	StaffEmail   string     `json:"staff_email" example:"john.doe@shuttlers.co"`
+	SoftwareID   uint       `json:"software_id" example:"3"`
+	Software     string     `json:"software" example:"Figma"`
+	Source       string     `json:"source" example:"department"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"` // Empty when no usage was ever recorded
+	IdleDays     int        `json:"idle_days" example:"74"`
+	ReclaimAfter *time.Time `json:"reclaim_after,omitempty"` // When an enabled reclamation policy will revoke the seat
+	Currency     string     `json:"currency" example:"USD"`
+	MonthlyCost  float64    `json:"monthly_cost" example:"15"`
+}
+
+// IdleSeatReport lists idle seats with their total monthly cost per currency.
+// swagger:model
+type IdleSeatReport struct {
+	Seats       []IdleSeat         `json:"seats"`
+	MonthlyCost map[string]float64 `json:"monthly_cost"`
+	AnnualCost  map[string]float64 `json:"annual_cost"`
+}
+
+// BudgetStatus compares a department's annual software budget with its committed and forecast spend.
+// Only seats priced in the budget currency are counted; other currencies are listed separately.
+// swagger:model
+type BudgetStatus struct {
+	DepartmentID     uint               `json:"department_id" example:"2"`
+	Department       string             `json:"department" example:"Engineering"`
+	Year             int                `json:"year" example:"2025"`
+	Currency         string             `json:"currency" example:"USD"`
+	AnnualBudget     float64            `json:"annual_budget" example:"50000"`
+	Enforcement      string             `json:"enforcement" example:"warn"`
+	CommittedMonthly float64            `json:"committed_monthly" example:"3800"` // Monthly cost of the seats held right now
+	CommittedAnnual  float64            `json:"committed_annual" example:"45600"`
+	SpentToDate      float64            `json:"spent_to_date" example:"19000"` // Prorated cost of the completed months of the year
+	Forecast         float64            `json:"forecast" example:"45600"`      // Spent to date plus the committed monthly cost for the rest of the year
+	Remaining        float64            `json:"remaining" example:"4400"`      // Budget left after the forecast
+	Utilization      float64            `json:"utilization" example:"0.912"`   // Forecast as a fraction of the budget
+	OverBudget       bool               `json:"over_budget" example:"false"`
+	OtherCurrencies  map[string]float64 `json:"other_currencies"` // Committed monthly cost of seats priced in other currencies
+}
@@ -5,14 +5,37 @@ import "time"
 // Software represents a piece of software managed by the system.
 // swagger:model
 type Software struct {
-	ID          uint      `gorm:"primaryKey" json:"id" example:"1"`
-	Name        string    `gorm:"unique" json:"name" example:"ClickUp"`
-	Description string    `json:"description" example:"Communication Suite"`
-	Type        string    `json:"type" example:"SaaS"` // e.g., License, SaaS, etc.
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Name          string    `gorm:"unique" json:"name" example:"ClickUp"`
+	Description   string    `json:"description" example:"Communication Suite"`
+	Type          string    `json:"type" example:"SaaS"`                      // e.g., License, SaaS, etc.
+	SeatLimit     int       `gorm:"default:0" json:"seat_limit" example:"50"` // Purchased seats, 0 means unlimited
+	SeatPrice     float64   `gorm:"default:0" json:"seat_price" example:"12.5"`
+	BillingPeriod string    `gorm:"default:'monthly'" json:"billing_period" example:"monthly"` // monthly, quarterly or annual
+	Currency      string    `gorm:"default:'USD'" json:"currency" example:"USD"`
+	OwnerID       *uint     `gorm:"index" json:"owner_id" example:"4"`        // Staff member who approves access and answers for its cost
+	BackupOwnerID *uint     `gorm:"index" json:"backup_owner_id" example:"9"` // Takes over when the owner leaves
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 func (Software) TableName() string {
 	return "software"
 }
+
+// OwnedSoftware is a software together with the role a staff member holds as its owner.
+// swagger:model
+type OwnedSoftware struct {
+	Software
+	Role string `json:"role" example:"owner"` // "owner", "backup_owner"
+}
+
+// SeatUsage summarises the purchased, used and free seats of a software.
+// swagger:model
+type SeatUsage struct {
+	SoftwareID uint `json:"software_id" example:"1"`
+	Purchased  int  `json:"purchased" example:"50"`
+	Used       int  `json:"used" example:"42"`
+	Free       int  `json:"free" example:"8"`
+	Unlimited  bool `json:"unlimited" example:"false"`
+}
@@ -3,12 +3,14 @@ package models
 import "time"
 
 // SoftwareAssignment defines a scoped software assignment (e.g. team, department).
+// Every staff member in scope is auto-assigned the software with source "rule".
 // swagger:model
 type SoftwareAssignment struct {
 	ID         uint      `json:"id" gorm:"primaryKey" example:"1"`
 	SoftwareID uint      `json:"software_id" example:"2"`
 	ScopeType  string    `json:"scope_type" example:"Team"` // "Department", "Team", "Staff"
 	ScopeID    uint      `json:"scope_id" example:"3"`
+	PlanID     *uint     `json:"plan_id" gorm:"index" example:"1"` // PlanID optionally names the plan of the software granted by this rule
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
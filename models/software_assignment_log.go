@@ -10,7 +10,7 @@ type SoftwareAssignmentLog struct {
 	Staff      StaffPlain `json:"staff" gorm:"foreignKey:StaffID"`
 	SoftwareID uint       `json:"software_id" example:"7"`
 	Software   Software   `json:"software" gorm:"foreignKey:SoftwareID"`
-	Action     string     `json:"action" example:"Assigned"` // Assigned | Unassigned
+	Action     string     `json:"action" example:"Assigned"` // Assigned | Unassigned | Reclaimed | Expired | Unassigned (Access Review) | Unassigned (Offboarding)
 	ChangedBy  uint       `json:"changed_by" example:"2"`
 	ChangedAt  time.Time  `json:"changed_at" example:"2025-06-11T15:04:05Z"`
 	UpdatedAt  time.Time  `json:"updated_at" example:"2025-06-11T15:05:00Z"`
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SoftwareAttributeRule grants a software to every staff member whose attributes match Condition,
+// e.g. `status == "Active" AND department == "Engineering" AND employment_type != "contractor"`.
+// swagger:model
+type SoftwareAttributeRule struct {
+	ID         uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Name       string    `gorm:"not null" json:"name" example:"GitHub for engineering employees"`
+	SoftwareID uint      `gorm:"index;not null" json:"software_id" example:"3"`
+	Software   *Software `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`
+	PlanID     *uint     `gorm:"index" json:"plan_id" example:"1"` // PlanID optionally names the plan of the software granted by this rule
+	Condition  string    `gorm:"type:text;not null" json:"condition" example:"status == \"Active\" AND email ends_with \"@shuttlers.co\""`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (SoftwareAttributeRule) TableName() string {
+	return "software_attribute_rules"
+}
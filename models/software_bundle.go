@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// SoftwareBundle is a named set of software, e.g. "Engineering Starter Kit", that can be assigned to a
+// staff member or used in a match like a single software. It expands into one assignment per item.
+// swagger:model
+type SoftwareBundle struct {
+	ID          uint                 `gorm:"primaryKey" json:"id" example:"1"`
+	Name        string               `gorm:"unique;not null" json:"name" example:"Engineering Starter Kit"`
+	Description string               `json:"description" example:"GitHub, Jira, Slack and 1Password for new engineers"`
+	Items       []SoftwareBundleItem `gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+func (SoftwareBundle) TableName() string {
+	return "software_bundles"
+}
+
+// SoftwareBundleItem is one software of a bundle, optionally at a plan.
+// swagger:model
+type SoftwareBundleItem struct {
+	ID         uint      `gorm:"primaryKey" json:"id" example:"1"`
+	BundleID   uint      `gorm:"uniqueIndex:idx_bundle_item;not null" json:"bundle_id" example:"1"`
+	SoftwareID uint      `gorm:"uniqueIndex:idx_bundle_item;not null" json:"software_id" example:"3"`
+	Software   *Software `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`
+	PlanID     *uint     `json:"plan_id" example:"1"`
+}
+
+func (SoftwareBundleItem) TableName() string {
+	return "software_bundle_items"
+}
+
+// BundleAssignment gives a bundle directly to a staff member.
+// swagger:model
+type BundleAssignment struct {
+	ID        uint      `gorm:"primaryKey" json:"id" example:"1"`
+	BundleID  uint      `gorm:"uniqueIndex:idx_bundle_assignment;not null" json:"bundle_id" example:"1"`
+	StaffID   uint      `gorm:"uniqueIndex:idx_bundle_assignment;not null" json:"staff_id" example:"2"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (BundleAssignment) TableName() string {
+	return "bundle_assignments"
+}
+
+// SoftwareBundleMatch gives a bundle to every staff member of the organization, a department or a team,
+// the way organization, department and team matches do for a single software.
+// swagger:model
+type SoftwareBundleMatch struct {
+	ID        uint            `gorm:"primaryKey" json:"id" example:"1"`
+	BundleID  uint            `gorm:"index;not null" json:"bundle_id" example:"1"`
+	Bundle    *SoftwareBundle `gorm:"foreignKey:BundleID" json:"bundle,omitempty"`
+	ScopeType string          `gorm:"size:20;not null" json:"scope_type" example:"Department"` // "Organization", "Department", "Team"
+	ScopeID   uint            `gorm:"not null;default:0" json:"scope_id" example:"3"`          // 0 for the organization
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (SoftwareBundleMatch) TableName() string {
+	return "software_bundle_matches"
+}
@@ -17,6 +17,10 @@ type SoftwareDepartmentMatch struct {
 	// example: 3
 	DepartmentID uint `json:"department_id"`
 
+	// PlanID optionally names the plan of the software granted by this match
+	// example: 1
+	PlanID *uint `json:"plan_id"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SoftwareExclusion keeps a staff member from being given a software by any match or rule,
+// e.g. contractors who must never get Zoom. An exclusion without ExpiresAt never lapses.
+// swagger:model
+type SoftwareExclusion struct {
+	ID         uint       `gorm:"primaryKey" json:"id" example:"1"`
+	StaffID    uint       `gorm:"uniqueIndex:idx_software_exclusion;not null" json:"staff_id" example:"2"`
+	SoftwareID uint       `gorm:"uniqueIndex:idx_software_exclusion;not null" json:"software_id" example:"3"`
+	Software   *Software  `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`
+	Reason     string     `gorm:"type:text" json:"reason" example:"Contractors use the client's video tooling"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2026-12-31T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (SoftwareExclusion) TableName() string {
+	return "software_exclusions"
+}
@@ -16,6 +16,10 @@ type SoftwareOrganizationMatch struct {
 	// Software holds detailed software information
 	Software Software `json:"software" gorm:"foreignKey:SoftwareID"`
 
+	// PlanID optionally names the plan of the software granted by this match
+	// example: 1
+	PlanID *uint `json:"plan_id"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
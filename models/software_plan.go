@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SoftwarePlan is an edition of a software (e.g. ClickUp Business vs Enterprise) with its own price.
+// swagger:model
+type SoftwarePlan struct {
+	ID            uint      `gorm:"primaryKey" json:"id" example:"1"`
+	SoftwareID    uint      `gorm:"uniqueIndex:idx_software_plan_name;not null" json:"software_id" example:"2"`
+	Name          string    `gorm:"uniqueIndex:idx_software_plan_name;size:100;not null" json:"name" example:"Enterprise"`
+	Tier          int       `gorm:"default:0" json:"tier" example:"2"` // Higher tier = higher plan; used to pick the best plan a staff member is entitled to
+	SeatPrice     float64   `gorm:"default:0" json:"seat_price" example:"29"`
+	BillingPeriod string    `gorm:"default:'monthly'" json:"billing_period" example:"monthly"` // monthly, quarterly or annual
+	Currency      string    `gorm:"default:'USD'" json:"currency" example:"USD"`
+	Features      string    `json:"features" example:"SSO, audit logs, unlimited storage"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (SoftwarePlan) TableName() string {
+	return "software_plans"
+}
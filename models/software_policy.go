@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SoftwarePolicy constrains which software a staff member may hold together. A "conflict" policy makes
+// two software mutually exclusive, e.g. only one password manager per person; a "requires" policy
+// only allows SoftwareID for staff holding RelatedSoftwareID, e.g. Jira Service Management needs Jira.
+// swagger:model
+type SoftwarePolicy struct {
+	ID                uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Kind              string    `gorm:"size:20;not null" json:"kind" example:"requires"` // "conflict", "requires"
+	SoftwareID        uint      `gorm:"index;not null" json:"software_id" example:"7"`
+	Software          *Software `gorm:"foreignKey:SoftwareID" json:"software,omitempty"`
+	RelatedSoftwareID uint      `gorm:"index;not null" json:"related_software_id" example:"6"`
+	RelatedSoftware   *Software `gorm:"foreignKey:RelatedSoftwareID" json:"related_software,omitempty"`
+	Description       string    `gorm:"type:text" json:"description" example:"Jira Service Management agents need a Jira seat"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (SoftwarePolicy) TableName() string {
+	return "software_policies"
+}
+
+// PolicyViolation is an assignment that breaks a software policy.
+// swagger:model
+type PolicyViolation struct {
+	PolicyID          uint   `json:"policy_id" example:"1"`
+	Kind              string `json:"kind" example:"requires"`
+	StaffID           uint   `json:"staff_id" example:"2"`
+	StaffEmail        string `json:"staff_email" example:"john.doe@shuttlers.co"`
+	SoftwareID        uint   `json:"software_id" example:"7"`
+	Software          string `json:"software" example:"Jira Service Management"`
+	RelatedSoftwareID uint   `json:"related_software_id" example:"6"`
+	RelatedSoftware   string `json:"related_software" example:"Jira"`
+	Message           string `json:"message" example:"Jira Service Management requires Jira"`
+}
@@ -21,6 +21,10 @@ type SoftwareTeamMatch struct {
 
 	Team Team `json:"team" gorm:"foreignKey:TeamID"`
 
+	// PlanID optionally names the plan of the software granted by this match
+	// example: 1
+	PlanID *uint `json:"plan_id" example:"1"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
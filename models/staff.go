@@ -5,15 +5,18 @@ import "time"
 // Staff represents a simple view employee in the organization.
 // swagger:model
 type StaffPlain struct {
-	ID           uint      `gorm:"primaryKey" json:"id" example:"1"`
-	FirstName    string    `json:"first_name" example:"John"`
-	LastName     string    `json:"last_name" example:"Doe"`
-	Email        string    `gorm:"unique" json:"email" example:"john.doe@shuttlers.co"`
-	DepartmentID uint      `json:"department_id" example:"2"`
-	TeamID       uint      `json:"team_id" example:"5"`
-	Status       string    `json:"status"  example:"Active"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             uint       `gorm:"primaryKey" json:"id" example:"1"`
+	FirstName      string     `json:"first_name" example:"John"`
+	LastName       string     `json:"last_name" example:"Doe"`
+	Email          string     `gorm:"unique" json:"email" example:"john.doe@shuttlers.co"`
+	DepartmentID   uint       `json:"department_id" example:"2"`
+	TeamID         uint       `json:"team_id" example:"5"`
+	Status         string     `json:"status"  example:"Active"`
+	EmploymentType string     `gorm:"default:'employee'" json:"employment_type" example:"employee"` // e.g. employee, contractor, intern
+	StartDate      *time.Time `json:"start_date" example:"2025-09-01T00:00:00Z"`                    // First working day; software activates then
+	ManagerID      *uint      `gorm:"index" json:"manager_id" example:"4"`                          // Line manager, who reviews the staff member's access
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 func (StaffPlain) TableName() string {
@@ -23,17 +26,20 @@ func (StaffPlain) TableName() string {
 // StaffWithDetail represents an enriched view of the employee in the organization.
 // swagger:model
 type Staff struct {
-	ID           uint       `gorm:"primaryKey" json:"id" example:"1"`
-	FirstName    string     `json:"first_name" example:"John"`
-	LastName     string     `json:"last_name" example:"Doe"`
-	Email        string     `gorm:"unique" json:"email" example:"john.doe@shuttlers.co"`
-	DepartmentID uint       `json:"department_id" example:"2"`
-	Department   Department `gorm:"foreignKey:DepartmentID" json:"department"`
-	TeamID       uint       `json:"team_id" example:"5"`
-	Team         Team       `gorm:"foreignKey:TeamID" json:"team"`
-	Status       string     `json:"status"  example:"Active"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID             uint       `gorm:"primaryKey" json:"id" example:"1"`
+	FirstName      string     `json:"first_name" example:"John"`
+	LastName       string     `json:"last_name" example:"Doe"`
+	Email          string     `gorm:"unique" json:"email" example:"john.doe@shuttlers.co"`
+	DepartmentID   uint       `json:"department_id" example:"2"`
+	Department     Department `gorm:"foreignKey:DepartmentID" json:"department"`
+	TeamID         uint       `json:"team_id" example:"5"`
+	Team           Team       `gorm:"foreignKey:TeamID" json:"team"`
+	Status         string     `json:"status"  example:"Active"`
+	EmploymentType string     `gorm:"default:'employee'" json:"employment_type" example:"employee"` // e.g. employee, contractor, intern
+	StartDate      *time.Time `json:"start_date" example:"2025-09-01T00:00:00Z"`                    // First working day; software activates then
+	ManagerID      *uint      `gorm:"index" json:"manager_id" example:"4"`                          // Line manager, who reviews the staff member's access
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 func (Staff) TableName() string {
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// UsageEvent is a single usage signal for a staff member and software, imported from a vendor admin console.
+// swagger:model
+type UsageEvent struct {
+	ID                 uint      `gorm:"primaryKey" json:"id" example:"1"`
+	StaffID            uint      `gorm:"index;not null" json:"staff_id" example:"2"`
+	SoftwareID         uint      `gorm:"index;not null" json:"software_id" example:"3"`
+	AssignedSoftwareID *uint     `gorm:"index" json:"assigned_software_id" example:"7"` // Empty when the staff member has no assignment for the software
+	EventType          string    `json:"event_type" example:"login"`
+	OccurredAt         time.Time `gorm:"index" json:"occurred_at" example:"2025-06-11T15:04:05Z"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (UsageEvent) TableName() string {
+	return "usage_events"
+}
+
+// UsageRecord is one row of a JSONL or CSV usage import. Staff and software can be given by ID or by email/name.
+type UsageRecord struct {
+	Line       int    `json:"-"`
+	StaffID    uint   `json:"staff_id"`
+	StaffEmail string `json:"staff_email"`
+	SoftwareID uint   `json:"software_id"`
+	Software   string `json:"software"`
+	Timestamp  string `json:"timestamp"`
+	EventType  string `json:"event_type"`
+}
+
+// UsageImportError describes a rejected line of a usage import.
+type UsageImportError struct {
+	Line  int    `json:"line" example:"14"`
+	Error string `json:"error" example:"unknown staff email jane@shuttlers.co"`
+}
+
+// UsageImportResult summarises a usage import.
+// swagger:model
+type UsageImportResult struct {
+	Received           int                `json:"received" example:"1200"`
+	Imported           int                `json:"imported" example:"1187"`
+	MatchedAssignments int                `json:"matched_assignments" example:"1150"` // Events that belonged to an existing assignment
+	Rejected           int                `json:"rejected" example:"13"`
+	Errors             []UsageImportError `json:"errors"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Vendor represents a company we buy software from.
+// swagger:model
+type Vendor struct {
+	ID           uint      `gorm:"primaryKey" json:"id" example:"1"`
+	Name         string    `gorm:"unique;not null" json:"name" example:"ClickUp Inc."`
+	Website      string    `json:"website" example:"https://clickup.com"`
+	ContactName  string    `json:"contact_name" example:"Jane Smith"`
+	ContactEmail string    `json:"contact_email" example:"accounts@clickup.com"`
+	Notes        string    `json:"notes" example:"Billing queries go through the account manager"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (Vendor) TableName() string {
+	return "vendors"
+}
@@ -27,6 +27,7 @@ func RegisterRoutes() *gin.Engine {
 
 		// Nested: Teams under a Department
 		api.GET("/departments/:id/teams", controllers.GetTeamsByDepartment)
+		api.GET("/departments/:id/budget", controllers.GetDepartmentBudget)
 
 		// ===== Team Routes =====
 		api.GET("/teams", controllers.GetTeams)
@@ -49,6 +50,11 @@ func RegisterRoutes() *gin.Engine {
 		api.GET("/staff/:id/assigned-software", controllers.GetSoftwareAssignedToStaff)
 		api.GET("/staff/:id/assigned-software/detail", controllers.GetSoftwareAssignedToStaffWithDetails)
 		api.GET("/staff/:id/assigned-software/names", controllers.GetSoftwareNamesAssignedToStaff)
+		api.GET("/staff/:id/exclusions", controllers.GetSoftwareExclusionsForStaff)
+		api.GET("/staff/:id/owned-software", controllers.GetSoftwareOwnedByStaff)
+		api.GET("/staff/:id/onboarding", controllers.GetOnboardingForStaff)
+		api.GET("/staff/:id/offboarding", controllers.GetOffboardingForStaff)
+		api.POST("/staff/:id/offboarding", controllers.ScheduleStaffOffboarding)
 		api.GET("/staff/:id/logs", controllers.GetAssignmentLogsForStaff)
 
 		// ===== Software Routes =====
@@ -63,23 +69,116 @@ func RegisterRoutes() *gin.Engine {
 		// Nested: Software-related staff & logs
 		api.GET("/software/:id/assigned-staff", controllers.GetStaffAssignedToSoftware)
 		api.GET("/software/:id/assigned-staff/detail", controllers.GetStaffAssignedToSoftwareWithDetails)
+		api.GET("/software/:id/seats", controllers.GetSoftwareSeats)
+
+		// Nested: Plans/editions of a software
+		api.GET("/software/:id/plans", controllers.GetSoftwarePlans)
+		api.POST("/software/:id/plans", controllers.CreateSoftwarePlan)
+		api.PUT("/software-plans/:id", controllers.UpdateSoftwarePlan)
+		api.DELETE("/software-plans/:id", controllers.DeleteSoftwarePlan)
+
+		// ===== Seat Reclamation Policies =====
+		api.GET("/reclamation-policies", controllers.GetReclamationPolicies)
+		api.GET("/software/:id/reclamation-policy", controllers.GetReclamationPolicy)
+		api.PUT("/software/:id/reclamation-policy", controllers.SetReclamationPolicy)
+		api.DELETE("/software/:id/reclamation-policy", controllers.DeleteReclamationPolicy)
+
+		// ===== License Key Vault =====
+		api.GET("/software/:id/license-keys", controllers.GetLicenseKeysForSoftware)
+		api.POST("/software/:id/license-keys", controllers.CreateLicenseKeys)
+		api.DELETE("/license-keys/:id", controllers.DeleteLicenseKey)
+		api.POST("/license-keys/:id/reveal", controllers.RevealLicenseKey)
+		api.GET("/license-keys/:id/access-logs", controllers.GetLicenseKeyAccessLogs)
 
 		// ===== Manual Software Assignment Routes =====
 		api.GET("/software-assignments/plain", controllers.GetSoftwareAssignments)
 		api.GET("/software-assignments", controllers.GetSoftwareAssignmentsWithDetail)
-		api.GET("/api/software-assignments/:id", controllers.GetSoftwareAssignmentByID)
+		api.GET("/software-assignments/:id", controllers.GetSoftwareAssignmentByID)
 		api.POST("/software-assignments", controllers.CreateSoftwareAssignment)
 		api.PUT("/software-assignments/:id", controllers.UpdateSoftwareAssignment)
 		api.DELETE("/software-assignments/:id", controllers.DeleteSoftwareAssignment)
 		api.DELETE("/software-assignments/:id/force", controllers.DeleteSoftwareAssignmentWithForce)
 
+		// ===== Attribute-based Assignment Rules =====
+		api.GET("/software-attribute-rules", controllers.GetSoftwareAttributeRules)
+		api.GET("/software-attribute-rules/:id", controllers.GetSoftwareAttributeRuleByID)
+		api.POST("/software-attribute-rules", controllers.CreateSoftwareAttributeRule)
+		api.PUT("/software-attribute-rules/:id", controllers.UpdateSoftwareAttributeRule)
+		api.DELETE("/software-attribute-rules/:id", controllers.DeleteSoftwareAttributeRule)
+
+		// Per-staff software exclusions
+		api.GET("/software-exclusions", controllers.GetSoftwareExclusions)
+		api.GET("/software-exclusions/:id", controllers.GetSoftwareExclusionByID)
+		api.POST("/software-exclusions", controllers.CreateSoftwareExclusion)
+		api.PUT("/software-exclusions/:id", controllers.UpdateSoftwareExclusion)
+		api.DELETE("/software-exclusions/:id", controllers.DeleteSoftwareExclusion)
+
+		// ===== Software Conflict and Requires Policies =====
+		api.GET("/software-policies", controllers.GetSoftwarePolicies)
+		api.GET("/software-policies/violations", controllers.GetPolicyViolations)
+		api.GET("/software-policies/:id", controllers.GetSoftwarePolicyByID)
+		api.POST("/software-policies", controllers.CreateSoftwarePolicy)
+		api.PUT("/software-policies/:id", controllers.UpdateSoftwarePolicy)
+		api.DELETE("/software-policies/:id", controllers.DeleteSoftwarePolicy)
+
+		// ===== Software Bundles =====
+		api.GET("/software-bundles", controllers.GetSoftwareBundles)
+		api.GET("/software-bundles/:id", controllers.GetSoftwareBundleByID)
+		api.POST("/software-bundles", controllers.CreateSoftwareBundle)
+		api.PUT("/software-bundles/:id", controllers.UpdateSoftwareBundle)
+		api.DELETE("/software-bundles/:id", controllers.DeleteSoftwareBundle)
+		api.POST("/software-bundles/:id/assign", controllers.AssignSoftwareBundle)
+		api.DELETE("/software-bundles/:id/staff/:staff_id", controllers.UnassignSoftwareBundle)
+		api.GET("/software-bundle-matches", controllers.GetSoftwareBundleMatches)
+		api.POST("/software-bundle-matches", controllers.CreateSoftwareBundleMatch)
+		api.DELETE("/software-bundle-matches/:id", controllers.DeleteSoftwareBundleMatch)
+
 		// ===== Assigned Software Routes (Actual assignments) =====
 		api.GET("/assigned-software", controllers.GetAssignedSoftware)
+		api.GET("/assigned-software/expiring", controllers.GetExpiringAssignments)
 		api.POST("/assign-software", controllers.CreateAssignedSoftware)
 		api.PUT("/assigned-software/:id", controllers.UpdateAssignedSoftware)
 		api.DELETE("/assigned-software/:id/force", controllers.DeleteAssignedSoftware)
 		api.DELETE("/assigned-software/:id", controllers.DeleteAssignedSoftwareWithLogging)
 
+		// ===== Software Access Requests =====
+		api.GET("/access-requests", controllers.GetAccessRequests)
+		api.GET("/access-requests/:id", controllers.GetAccessRequestByID)
+		api.POST("/access-requests", controllers.SubmitAccessRequest)
+		api.POST("/access-requests/:id/approve", controllers.ApproveAccessRequest)
+		api.POST("/access-requests/:id/reject", controllers.RejectAccessRequest)
+		api.POST("/access-requests/:id/cancel", controllers.CancelAccessRequest)
+
+		// ===== Access Review Campaigns =====
+		api.GET("/access-reviews", controllers.GetAccessReviews)
+		api.GET("/access-reviews/:id", controllers.GetAccessReviewByID)
+		api.POST("/access-reviews", controllers.CreateAccessReview)
+		api.POST("/access-reviews/:id/complete", controllers.CompleteAccessReview)
+		api.GET("/access-reviews/:id/report", controllers.GetAccessReviewReport)
+		api.GET("/access-reviews/:id/items", controllers.GetAccessReviewItems)
+		api.POST("/access-review-items/:id/decision", controllers.DecideAccessReviewItem)
+		api.PUT("/access-review-items/:id/reviewer", controllers.ReassignAccessReviewItem)
+
+		// ===== Onboarding =====
+		api.GET("/onboardings", controllers.GetOnboardings)
+		api.GET("/onboardings/:id", controllers.GetOnboardingByID)
+		api.POST("/onboardings/:id/tasks", controllers.AddOnboardingTask)
+		api.POST("/onboardings/:id/sync", controllers.SyncOnboarding)
+		api.POST("/onboardings/:id/complete", controllers.CompleteOnboarding)
+		api.PUT("/onboarding-items/:id", controllers.UpdateOnboardingItem)
+
+		// ===== Offboarding =====
+		api.GET("/offboardings", controllers.GetOffboardings)
+		api.GET("/offboardings/:id", controllers.GetOffboardingByID)
+		api.POST("/offboardings/:id/execute", controllers.ExecuteOffboarding)
+		api.POST("/offboardings/:id/cancel", controllers.CancelOffboarding)
+		api.GET("/offboardings/:id/certificate", controllers.GetOffboardingCertificate)
+		api.PUT("/offboarding-items/:id", controllers.ReplanOffboardingItem)
+
+		// ===== Usage Events =====
+		api.GET("/usage-events", controllers.GetUsageEvents)
+		api.POST("/usage-events/import", controllers.ImportUsageEvents)
+
 		// ===== Software Assignment Logs =====
 		api.GET("/logs", controllers.GetAllAssignmentLogsWithDetails)
 		api.GET("/logs/details", controllers.GetAllAssignmentLogsWithDetails)
@@ -92,6 +191,33 @@ func RegisterRoutes() *gin.Engine {
 		api.GET("/logs/software/:id/plain", controllers.GetAssignmentLogsForSoftware)
 		api.GET("/logs/software/:id", controllers.GetAssignmentLogsForSoftwareWithDetails)
 
+		// ===== Vendor Routes =====
+		api.GET("/vendors", controllers.GetVendors)
+		api.GET("/vendors/:id", controllers.GetVendorByID)
+		api.POST("/vendors", controllers.CreateVendor)
+		api.PUT("/vendors/:id", controllers.UpdateVendor)
+		api.DELETE("/vendors/:id", controllers.DeleteVendor)
+
+		// ===== Contract Routes =====
+		api.GET("/contracts", controllers.GetContracts)
+		api.GET("/contracts/renewals", controllers.GetContractRenewals)
+		api.GET("/contracts/:id", controllers.GetContractByID)
+		api.POST("/contracts", controllers.CreateContract)
+		api.PUT("/contracts/:id", controllers.UpdateContract)
+		api.DELETE("/contracts/:id", controllers.DeleteContract)
+
+		// ===== Reminders =====
+		api.GET("/reminders", controllers.GetReminders)
+
+		// ===== Reports =====
+		api.GET("/reports/spend", controllers.GetSpendReport)
+		api.GET("/reports/chargeback", controllers.GetChargebackReport)
+		api.GET("/reports/idle-seats", controllers.GetIdleSeatReport)
+
+		// ===== Reconciliation =====
+		api.GET("/reconciliation/drift", controllers.GetDriftReport)
+		api.POST("/reconciliation/apply", controllers.ApplyReconciliation)
+
 		// ===== Auto-assignment Match Controllers =====
 
 		// Organization-level match routes
@@ -99,8 +225,10 @@ func RegisterRoutes() *gin.Engine {
 		{
 			orgMatch.GET("", controllers.GetSoftwareOrganizationMatches)
 			orgMatch.POST("", controllers.CreateSoftwareOrganizationMatch)
+			orgMatch.POST("/auto-assign", controllers.CreateSoftwareOrganizationMatchWithAutoAssignment)
 			orgMatch.PUT("/:id", controllers.UpdateSoftwareOrganizationMatch)
 			orgMatch.DELETE("/:id", controllers.DeleteSoftwareOrganizationMatch)
+			orgMatch.DELETE("/:id/revoke", controllers.DeleteSoftwareOrganizationMatchAndRevokeAssignment)
 		}
 
 		// Department-level match routes
@@ -108,8 +236,10 @@ func RegisterRoutes() *gin.Engine {
 		{
 			deptMatch.GET("", controllers.GetSoftwareDepartmentMatches)
 			deptMatch.POST("", controllers.CreateSoftwareDepartmentMatch)
+			deptMatch.POST("/auto-assign", controllers.CreateSoftwareDepartmentMatchWithAutoAssignment)
 			deptMatch.PUT("/:id", controllers.UpdateSoftwareDepartmentMatch)
 			deptMatch.DELETE("/:id", controllers.DeleteSoftwareDepartmentMatch)
+			deptMatch.DELETE("/:id/revoke", controllers.DeleteSoftwareDepartmentMatchAndRevokeAssignment)
 		}
 
 		// Team-level match routes
@@ -117,8 +247,10 @@ func RegisterRoutes() *gin.Engine {
 		{
 			teamMatch.GET("", controllers.GetSoftwareTeamMatches)
 			teamMatch.POST("", controllers.CreateSoftwareTeamMatch)
+			teamMatch.POST("/auto-assign", controllers.CreateSoftwareTeamMatchWithAutoAssignment)
 			teamMatch.PUT("/:id", controllers.UpdateSoftwareTeamMatch)
 			teamMatch.DELETE("/:id", controllers.DeleteSoftwareTeamMatch)
+			teamMatch.DELETE("/:id/revoke", controllers.DeleteSoftwareTeamMatchAndRevokeAssignments)
 		}
 	}
 
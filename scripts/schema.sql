@@ -2,6 +2,9 @@
 CREATE TABLE departments (
     id INT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL UNIQUE,
+    annual_budget DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    budget_currency CHAR(3) NOT NULL DEFAULT 'USD',
+    budget_enforcement ENUM('warn', 'reject') NOT NULL DEFAULT 'warn',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
@@ -23,11 +26,15 @@ CREATE TABLE staff (
     last_name VARCHAR(255) NOT NULL,
     email VARCHAR(255) NOT NULL UNIQUE,
     status ENUM('Active', 'Inactive') NOT NULL,
+    employment_type VARCHAR(50) NOT NULL DEFAULT 'employee',
+    start_date DATE NULL,
+    manager_id INT NULL, -- line manager, who reviews the staff member's access
     department_id INT NOT NULL,
     team_id INT NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     UNIQUE KEY idx_unique_email (email),
+    INDEX idx_staff_manager_id (manager_id),
     FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
 );
 
@@ -37,8 +44,32 @@ CREATE TABLE software (
     name VARCHAR(255) NOT NULL UNIQUE,
     description TEXT,
     type ENUM('License', 'SaaS', 'Subscription', 'Other') NOT NULL,
+    seat_limit INT NOT NULL DEFAULT 0, -- 0 means unlimited
+    seat_price DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    billing_period ENUM('monthly', 'quarterly', 'annual') NOT NULL DEFAULT 'monthly',
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    owner_id INT NULL, -- staff member who approves access and answers for its cost
+    backup_owner_id INT NULL, -- takes over when the owner leaves
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_software_owner_id (owner_id),
+    INDEX idx_software_backup_owner_id (backup_owner_id)
+);
+
+-- Table: software_plans (editions of a software, e.g. Business vs Enterprise)
+CREATE TABLE software_plans (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    software_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    tier INT NOT NULL DEFAULT 0,
+    seat_price DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    billing_period ENUM('monthly', 'quarterly', 'annual') NOT NULL DEFAULT 'monthly',
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    features TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_software_plan_name (software_id, name)
 );
 
 -- Table: software_assignments
@@ -47,9 +78,11 @@ CREATE TABLE software_assignments (
     software_id INT NOT NULL,
     scope_type ENUM('Department', 'Team', 'Staff') NOT NULL,
     scope_id INT NOT NULL,
+    plan_id INT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (plan_id) REFERENCES software_plans(id),
     INDEX idx_scope (scope_type, scope_id)
 );
 
@@ -58,12 +91,33 @@ CREATE TABLE assigned_software (
     id INT AUTO_INCREMENT PRIMARY KEY,
     staff_id INT NOT NULL,
     software_id INT NOT NULL,
-    source ENUM('manual', 'department', 'team', 'organization') NOT NULL DEFAULT 'manual',
+    plan_id INT NULL,
+    source ENUM('manual', 'department', 'team', 'organization', 'rule', 'bundle') NOT NULL DEFAULT 'manual',
     assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    status ENUM('pending', 'active') NOT NULL DEFAULT 'active',
+    starts_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    expires_at DATETIME NULL,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
     FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
-    UNIQUE (staff_id, software_id)
+    FOREIGN KEY (plan_id) REFERENCES software_plans(id),
+    UNIQUE (staff_id, software_id),
+    INDEX (expires_at),
+    INDEX (status, starts_at)
+);
+
+-- Table: assignment_grants (every match, rule or manual assignment behind an assignment)
+CREATE TABLE assignment_grants (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    assigned_software_id INT NOT NULL,
+    source_type VARCHAR(32) NOT NULL, -- manual | organization | department | team | rule | attribute_rule | bundle | bundle_match
+    source_id INT NOT NULL DEFAULT 0,
+    plan_id INT NULL,
+    bundle_id INT NULL, -- the bundle a bundle or bundle_match grant comes from
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (assigned_software_id) REFERENCES assigned_software(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_assignment_grant (assigned_software_id, source_type, source_id)
 );
 
 -- Table: software_assignment_logs
@@ -71,7 +125,7 @@ CREATE TABLE software_assignment_logs (
     id INT AUTO_INCREMENT PRIMARY KEY,
     staff_id INT NOT NULL,
     software_id INT NOT NULL,
-    action ENUM('Assigned', 'Unassigned') NOT NULL,
+    action ENUM('Assigned', 'Unassigned', 'Unassigned (Rule Deleted)', 'Reclaimed', 'Expired', 'Unassigned (Access Review)', 'Unassigned (Offboarding)') NOT NULL,
     changed_by INT NOT NULL,
     changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -86,6 +140,7 @@ CREATE TABLE software_assignment_logs (
 CREATE TABLE software_organization_matches (
     id INT AUTO_INCREMENT PRIMARY KEY,
     software_id INT NOT NULL,
+    plan_id INT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE
@@ -95,6 +150,7 @@ CREATE TABLE software_organization_matches (
 CREATE TABLE software_department_matches (
     id INT AUTO_INCREMENT PRIMARY KEY,
     software_id INT NOT NULL,
+    plan_id INT NULL,
     department_id INT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -106,9 +162,333 @@ CREATE TABLE software_department_matches (
 CREATE TABLE software_team_matches (
     id INT AUTO_INCREMENT PRIMARY KEY,
     software_id INT NOT NULL,
+    plan_id INT NULL,
     team_id INT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
     FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
 );
+
+-- Table: vendors
+CREATE TABLE vendors (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    website VARCHAR(255),
+    contact_name VARCHAR(255),
+    contact_email VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Table: contracts
+CREATE TABLE contracts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    vendor_id INT NOT NULL,
+    software_id INT NOT NULL,
+    contract_number VARCHAR(100) NOT NULL UNIQUE,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    auto_renew BOOLEAN NOT NULL DEFAULT FALSE,
+    notice_period_days INT NOT NULL DEFAULT 0,
+    seat_count INT NOT NULL DEFAULT 0,
+    value DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (vendor_id) REFERENCES vendors(id),
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    INDEX (end_date)
+);
+
+-- Table: reminders (each reminder fires once per dedup_key)
+CREATE TABLE reminders (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    dedup_key VARCHAR(255) NOT NULL UNIQUE,
+    contract_id INT NULL,
+    department_id INT NULL,
+    due_date DATETIME NOT NULL,
+    message TEXT,
+    fired_at DATETIME NOT NULL,
+    FOREIGN KEY (contract_id) REFERENCES contracts(id) ON DELETE CASCADE,
+    FOREIGN KEY (department_id) REFERENCES departments(id) ON DELETE CASCADE,
+    INDEX (kind),
+    INDEX (fired_at)
+);
+
+-- Table: license_keys (key material is AES-256-GCM encrypted with LICENSE_VAULT_KEY)
+CREATE TABLE license_keys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    software_id INT NOT NULL,
+    label VARCHAR(255),
+    encrypted_key TEXT NOT NULL,
+    key_hint VARCHAR(20),
+    assigned_software_id INT NULL,
+    allocated_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_software_id) REFERENCES assigned_software(id) ON DELETE SET NULL,
+    INDEX (software_id, assigned_software_id)
+);
+
+-- Table: license_key_access_logs (audit trail of plaintext reveals)
+CREATE TABLE license_key_access_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    license_key_id INT NOT NULL,
+    software_id INT NOT NULL,
+    requested_by INT NOT NULL,
+    reason TEXT,
+    client_ip VARCHAR(64),
+    accessed_at DATETIME NOT NULL,
+    FOREIGN KEY (license_key_id) REFERENCES license_keys(id) ON DELETE CASCADE,
+    INDEX (license_key_id),
+    INDEX (accessed_at)
+);
+
+-- Table: usage_events (imported from vendor admin consoles)
+CREATE TABLE usage_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    staff_id INT NOT NULL,
+    software_id INT NOT NULL,
+    assigned_software_id INT NULL,
+    event_type VARCHAR(50) NOT NULL DEFAULT 'active',
+    occurred_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_software_id) REFERENCES assigned_software(id) ON DELETE SET NULL,
+    INDEX (staff_id, software_id),
+    INDEX (occurred_at)
+);
+
+-- Table: reclamation_policies (revoke idle seats per software)
+CREATE TABLE reclamation_policies (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    software_id INT NOT NULL UNIQUE,
+    idle_days INT NOT NULL DEFAULT 60,
+    warning_days INT NOT NULL DEFAULT 7,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE
+);
+
+-- Table: software_attribute_rules (grant software to staff matching a condition)
+CREATE TABLE software_attribute_rules (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    software_id INT NOT NULL,
+    plan_id INT NULL,
+    `condition` TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (plan_id) REFERENCES software_plans(id)
+);
+
+-- Table: software_exclusions (staff who must never get a software through matches or rules)
+CREATE TABLE software_exclusions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    staff_id INT NOT NULL,
+    software_id INT NOT NULL,
+    reason TEXT,
+    expires_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_software_exclusion (staff_id, software_id)
+);
+
+-- Table: software_bundles (named sets of software assigned like a single software)
+CREATE TABLE software_bundles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Table: software_bundle_items
+CREATE TABLE software_bundle_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    bundle_id INT NOT NULL,
+    software_id INT NOT NULL,
+    plan_id INT NULL,
+    FOREIGN KEY (bundle_id) REFERENCES software_bundles(id) ON DELETE CASCADE,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (plan_id) REFERENCES software_plans(id),
+    UNIQUE KEY idx_bundle_item (bundle_id, software_id)
+);
+
+-- Table: bundle_assignments (bundles given directly to a staff member)
+CREATE TABLE bundle_assignments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    bundle_id INT NOT NULL,
+    staff_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (bundle_id) REFERENCES software_bundles(id) ON DELETE CASCADE,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_bundle_assignment (bundle_id, staff_id)
+);
+
+-- Table: software_bundle_matches (bundles given to the organization, a department or a team)
+CREATE TABLE software_bundle_matches (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    bundle_id INT NOT NULL,
+    scope_type VARCHAR(20) NOT NULL, -- Organization | Department | Team
+    scope_id INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (bundle_id) REFERENCES software_bundles(id),
+    INDEX idx_software_bundle_matches_bundle_id (bundle_id)
+);
+
+-- Table: software_policies (software that conflict with, or require, another software)
+CREATE TABLE software_policies (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL, -- conflict | requires
+    software_id INT NOT NULL,
+    related_software_id INT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_software_id) REFERENCES software(id) ON DELETE CASCADE,
+    INDEX idx_software_policies_software_id (software_id),
+    INDEX idx_software_policies_related_software_id (related_software_id)
+);
+
+-- Table: access_requests (staff asking for software, and the approver's decision)
+CREATE TABLE access_requests (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    staff_id INT NOT NULL,
+    software_id INT NOT NULL,
+    plan_id INT NULL,
+    justification TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending | approved | rejected | cancelled
+    approver_id INT NULL,
+    decision_note TEXT,
+    decided_at DATETIME NULL,
+    assigned_software_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_access_requests_staff_id (staff_id),
+    INDEX idx_access_requests_software_id (software_id),
+    INDEX idx_access_requests_status (status),
+    INDEX idx_access_requests_approver_id (approver_id)
+);
+
+-- Table: access_review_campaigns (periodic certification that staff still need their access)
+CREATE TABLE access_review_campaigns (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    scope_type VARCHAR(20) NOT NULL, -- Organization | Department | Software
+    scope_id INT NOT NULL DEFAULT 0,
+    reviewer VARCHAR(20) NOT NULL DEFAULT 'manager', -- manager | owner
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open | completed
+    due_date DATETIME NULL,
+    completed_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_access_review_campaigns_status (status)
+);
+
+-- Table: access_review_items (snapshot of an assignment under review and the reviewer's decision)
+CREATE TABLE access_review_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    campaign_id INT NOT NULL,
+    assigned_software_id INT NOT NULL,
+    staff_id INT NOT NULL,
+    software_id INT NOT NULL,
+    plan_id INT NULL,
+    source VARCHAR(20),
+    reviewer_id INT NULL,
+    decision VARCHAR(20), -- NULL or empty while pending, keep | revoke
+    note TEXT,
+    decided_at DATETIME NULL,
+    exclusion_id INT NULL, -- exclusion a revoke left behind
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES access_review_campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    FOREIGN KEY (software_id) REFERENCES software(id) ON DELETE CASCADE,
+    INDEX idx_access_review_items_campaign_id (campaign_id),
+    INDEX idx_access_review_items_assigned_software_id (assigned_software_id),
+    INDEX idx_access_review_items_staff_id (staff_id),
+    INDEX idx_access_review_items_software_id (software_id),
+    INDEX idx_access_review_items_reviewer_id (reviewer_id)
+);
+
+-- Table: onboardings (checklist of what a new staff member should get)
+CREATE TABLE onboardings (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    staff_id INT NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open | completed
+    completed_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    INDEX idx_onboardings_status (status)
+);
+
+-- Table: onboarding_items (expected software and manual tasks of an onboarding)
+CREATE TABLE onboarding_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    onboarding_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- software | task
+    software_id INT NULL,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- software: pending | provisioned | failed | skipped; task: pending | done | skipped
+    detail TEXT,
+    completed_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (onboarding_id) REFERENCES onboardings(id) ON DELETE CASCADE,
+    INDEX idx_onboarding_items_onboarding_id (onboarding_id)
+);
+
+-- Table: offboardings (a staff member's departure, deprovisioned on the effective date)
+CREATE TABLE offboardings (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    staff_id INT NOT NULL,
+    effective_date DATETIME NOT NULL,
+    manual_handling VARCHAR(20) NOT NULL DEFAULT 'revoke', -- revoke | transfer | keep, for manually assigned software
+    transfer_to_id INT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- scheduled | in_progress | completed | cancelled
+    forced BOOLEAN NOT NULL DEFAULT FALSE, -- runs even when it leaves software without an active owner
+    completed_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    INDEX idx_offboardings_staff_id (staff_id),
+    INDEX idx_offboardings_effective_date (effective_date),
+    INDEX idx_offboardings_status (status)
+);
+
+-- Table: offboarding_items (deprovisioning of each software the leaver holds)
+CREATE TABLE offboarding_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    offboarding_id INT NOT NULL,
+    assigned_software_id INT NOT NULL,
+    software_id INT NOT NULL,
+    software VARCHAR(255) NOT NULL, -- name at the time, kept for the certificate
+    source VARCHAR(20), -- manual | auto
+    action VARCHAR(20) NOT NULL, -- revoke | transfer | keep
+    transfer_to_id INT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending | revoked | transferred | kept | failed
+    detail TEXT,
+    processed_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (offboarding_id) REFERENCES offboardings(id) ON DELETE CASCADE,
+    INDEX idx_offboarding_items_offboarding_id (offboarding_id),
+    INDEX idx_offboarding_items_assigned_software_id (assigned_software_id),
+    INDEX idx_offboarding_items_software_id (software_id)
+);
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestAccessRequest(t *testing.T, staffID, softwareID uint) models.AccessRequest {
+	t.Helper()
+	request := models.AccessRequest{StaffID: staffID, SoftwareID: softwareID, Justification: "Needed for the redesign", Status: utils.RequestPending}
+	if err := config.DB.Create(&request).Error; err != nil {
+		t.Fatalf("create access request: %v", err)
+	}
+	return request
+}
+
+func TestApprovalAssignsAndLogsOnce(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	requester := createTestStaff(t, "designer@shuttlers.co", 0, 0)
+	approver := createTestStaff(t, "manager@shuttlers.co", 0, 0)
+	request := createTestAccessRequest(t, requester.ID, software.ID)
+
+	assert.NoError(t, utils.ApproveAccessRequest(&request, approver.ID, "ok"))
+	assert.Equal(t, utils.RequestApproved, request.Status)
+	assert.NotNil(t, request.AssignedSoftwareID)
+	assert.Equal(t, int64(1), heldSoftware(requester.ID, software.ID))
+
+	assert.ErrorIs(t, utils.ApproveAccessRequest(&request, approver.ID, "again"), utils.ErrRequestNotPending)
+	var logs int64
+	config.DB.Model(&models.SoftwareAssignmentLog{}).Where("staff_id = ? AND changed_by = ?", requester.ID, approver.ID).Count(&logs)
+	assert.Equal(t, int64(1), logs)
+}
+
+func TestFailedApprovalLeavesRequestPending(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 1)
+	holder := createTestStaff(t, "holder@shuttlers.co", 0, 0)
+	requester := createTestStaff(t, "designer@shuttlers.co", 0, 0)
+	approver := createTestStaff(t, "manager@shuttlers.co", 0, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: holder.ID, SoftwareID: software.ID, Source: utils.SourceManual}))
+	request := createTestAccessRequest(t, requester.ID, software.ID)
+
+	assert.ErrorIs(t, utils.ApproveAccessRequest(&request, approver.ID, "ok"), utils.ErrNoSeatsAvailable)
+
+	var stored models.AccessRequest
+	config.DB.First(&stored, request.ID)
+	assert.Equal(t, utils.RequestPending, stored.Status)
+	assert.Nil(t, stored.ApproverID)
+	assert.Nil(t, stored.DecidedAt)
+	assert.Zero(t, heldSoftware(requester.ID, software.ID))
+	var logs int64
+	config.DB.Model(&models.SoftwareAssignmentLog{}).Where("staff_id = ?", requester.ID).Count(&logs)
+	assert.Zero(t, logs)
+}
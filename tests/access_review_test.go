@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseReviewer(t *testing.T) {
+	staff, manager, owner, backup := uint(1), uint(2), uint(3), uint(4)
+
+	assert.Equal(t, &manager, utils.ChooseReviewer(utils.ReviewByManager, staff, &manager, &owner, &backup))
+	assert.Equal(t, &owner, utils.ChooseReviewer(utils.ReviewByOwner, staff, &manager, &owner, &backup))
+
+	// Falls back to whoever else is known
+	assert.Equal(t, &owner, utils.ChooseReviewer(utils.ReviewByManager, staff, nil, &owner, &backup))
+	assert.Equal(t, &backup, utils.ChooseReviewer(utils.ReviewByOwner, staff, &manager, nil, &backup))
+	assert.Equal(t, &manager, utils.ChooseReviewer(utils.ReviewByOwner, staff, &manager, nil, nil))
+
+	// Owners never review their own access
+	assert.Equal(t, &backup, utils.ChooseReviewer(utils.ReviewByOwner, owner, nil, &owner, &backup))
+	assert.Nil(t, utils.ChooseReviewer(utils.ReviewByManager, owner, nil, &owner, nil))
+}
+
+// openTestReview opens an organization-wide campaign over the department-granted Figma of one staff member
+func openTestReview(t *testing.T) (models.AccessReviewCampaign, models.AccessReviewItem, models.StaffPlain, models.Software) {
+	t.Helper()
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 0)
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, []models.Staff{{ID: staff.ID}}, utils.SourceDepartment, 1)
+
+	campaign := models.AccessReviewCampaign{Name: "Q3 review", ScopeType: utils.ScopeOrganization, Reviewer: utils.ReviewByManager}
+	if err := utils.OpenAccessReview(&campaign); err != nil {
+		t.Fatalf("open access review: %v", err)
+	}
+	var item models.AccessReviewItem
+	if err := config.DB.Where("campaign_id = ?", campaign.ID).First(&item).Error; err != nil {
+		t.Fatalf("load review item: %v", err)
+	}
+	return campaign, item, staff, software
+}
+
+func TestRevokeDecisionExcludesAndReports(t *testing.T) {
+	UseTestDB(t)
+	campaign, item, staff, software := openTestReview(t)
+
+	assert.NoError(t, utils.DecideReviewItem(&item, 9, utils.ReviewRevoke, "no longer needed"))
+	assert.Equal(t, utils.ReviewRevoke, item.Decision)
+	assert.Zero(t, heldSoftware(staff.ID, software.ID))
+	if assert.NotNil(t, item.ExclusionID) {
+		var exclusion models.SoftwareExclusion
+		assert.NoError(t, config.DB.First(&exclusion, *item.ExclusionID).Error)
+		assert.Equal(t, staff.ID, exclusion.StaffID)
+		assert.Nil(t, exclusion.ExpiresAt, "the exclusion stands until removed")
+	}
+	var logs int64
+	config.DB.Model(&models.SoftwareAssignmentLog{}).Where("staff_id = ? AND action = ?", staff.ID, utils.ActionReviewed).Count(&logs)
+	assert.Equal(t, int64(1), logs)
+
+	report, err := utils.AccessReviewSummary(campaign)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Revoked)
+	if assert.Len(t, report.Exclusions, 1) {
+		assert.Equal(t, *item.ExclusionID, report.Exclusions[0].ID)
+	}
+}
+
+func TestFailedRevokeRollsBackDecision(t *testing.T) {
+	UseTestDB(t)
+	_, item, staff, software := openTestReview(t)
+
+	config.DB.Exec("CREATE TRIGGER fail_exclusion BEFORE INSERT ON software_exclusions BEGIN SELECT RAISE(ABORT, 'exclusion refused'); END")
+	defer config.DB.Exec("DROP TRIGGER fail_exclusion")
+
+	assert.Error(t, utils.DecideReviewItem(&item, 9, utils.ReviewRevoke, "no longer needed"))
+	var stored models.AccessReviewItem
+	config.DB.First(&stored, item.ID)
+	assert.Empty(t, stored.Decision, "the decision is rolled back with the revoke")
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID))
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryIsRefusedOnMatchedSoftware(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 0)
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, []models.Staff{{ID: staff.ID}}, utils.SourceDepartment, 1)
+
+	expiresAt := time.Now().AddDate(0, 0, 7)
+	w, _ := PerformRequest("POST", "/api/assign-software", map[string]interface{}{
+		"staff_id": staff.ID, "software_id": software.ID, "expires_at": expiresAt,
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var assignment models.AssignedSoftware
+	config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, software.ID).First(&assignment)
+	assert.Nil(t, assignment.ExpiresAt)
+	assert.Equal(t, int64(1), grantCount(staff.ID, software.ID), "no manual grant is added")
+}
+
+func TestExpiryOfManualAssignment(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 0, 0)
+
+	expiresAt := time.Now().AddDate(0, 0, 7)
+	w, _ := PerformRequest("POST", "/api/assign-software", map[string]interface{}{
+		"staff_id": staff.ID, "software_id": software.ID, "expires_at": expiresAt,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Once the expiry has passed the job revokes it and logs why
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ?", staff.ID).Update("expires_at", time.Now().Add(-time.Hour))
+	assert.NoError(t, utils.ProcessAssignmentExpiry())
+	assert.Zero(t, heldSoftware(staff.ID, software.ID))
+
+	var logs int64
+	config.DB.Model(&models.SoftwareAssignmentLog{}).Where("staff_id = ? AND action = ?", staff.ID, utils.ActionExpired).Count(&logs)
+	assert.Equal(t, int64(1), logs)
+}
+
+func TestExpiryKeepsSoftwareStillGrantedByAMatch(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 0)
+	past := time.Now().Add(-time.Hour)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: software.ID, Source: utils.SourceManual}))
+	// The department match came after the time-bound manual assignment
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, []models.Staff{{ID: staff.ID}}, utils.SourceDepartment, 1)
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ?", staff.ID).Update("expires_at", past)
+
+	assert.NoError(t, utils.ProcessAssignmentExpiry())
+	var assignment models.AssignedSoftware
+	assert.NoError(t, config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, software.ID).First(&assignment).Error)
+	assert.Nil(t, assignment.ExpiresAt)
+	assert.Equal(t, utils.SourceDepartment, assignment.Source)
+}
+
+func TestExpiryIsRefusedOnUpdateOfMatchedSoftware(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 0)
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, []models.Staff{{ID: staff.ID}}, utils.SourceDepartment, 1)
+	var assignment models.AssignedSoftware
+	config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, software.ID).First(&assignment)
+
+	w, _ := PerformRequest("PUT", "/api/assigned-software/"+itoa(assignment.ID), map[string]interface{}{
+		"expires_at": time.Now().AddDate(0, 0, 7),
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	config.DB.First(&assignment, assignment.ID)
+	assert.Nil(t, assignment.ExpiresAt)
+
+	// A manual assignment can be given one
+	manual := createTestSoftware(t, "Miro", 0)
+	record := models.AssignedSoftware{StaffID: staff.ID, SoftwareID: manual.ID, Source: utils.SourceManual}
+	assert.NoError(t, utils.CreateAssignment(&record))
+	w, _ = PerformRequest("PUT", "/api/assigned-software/"+itoa(record.ID), map[string]interface{}{
+		"expires_at": time.Now().AddDate(0, 0, 7),
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	config.DB.First(&record, record.ID)
+	assert.NotNil(t, record.ExpiresAt)
+}
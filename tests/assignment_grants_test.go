@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func grantCount(staffID, softwareID uint) int64 {
+	var grants int64
+	config.DB.Model(&models.AssignmentGrant{}).
+		Joins("JOIN assigned_software ON assigned_software.id = assignment_grants.assigned_software_id").
+		Where("assigned_software.staff_id = ? AND assigned_software.software_id = ?", staffID, softwareID).
+		Count(&grants)
+	return grants
+}
+
+func TestSoftwareIsRevokedWithItsLastGrant(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 2)
+	staffList := []models.Staff{{ID: staff.ID}}
+
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, staffList, utils.SourceDepartment, 1)
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, staffList, utils.SourceTeam, 2)
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID))
+	assert.Equal(t, int64(2), grantCount(staff.ID, software.ID))
+
+	utils.AutoRevokeSoftwareFromStaff(software.ID, staffList, utils.SourceDepartment, 1)
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID), "the team match still grants it")
+	assert.Equal(t, int64(1), grantCount(staff.ID, software.ID))
+
+	utils.AutoRevokeSoftwareFromStaff(software.ID, staffList, utils.SourceTeam, 2)
+	assert.Zero(t, heldSoftware(staff.ID, software.ID))
+	assert.Zero(t, grantCount(staff.ID, software.ID))
+}
+
+func TestRevokingAutoGrantsKeepsManualAssignment(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 1, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: software.ID, Source: utils.SourceManual}))
+	utils.AutoAssignSoftwareToStaffByUnit(software.ID, nil, []models.Staff{{ID: staff.ID}}, utils.SourceDepartment, 1)
+	assert.Equal(t, int64(2), grantCount(staff.ID, software.ID))
+
+	assert.NoError(t, utils.RevokeSoftwareAssignmentsForStaff(staff.ID))
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID), "the manual grant keeps it")
+
+	var assignment models.AssignedSoftware
+	config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, software.ID).First(&assignment)
+	assert.Equal(t, utils.SourceManual, assignment.Source)
+}
@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRuleScope(t *testing.T) {
+	cases := map[string]string{
+		"Department": utils.ScopeDepartment,
+		"team":       utils.ScopeTeam,
+		"STAFF":      utils.ScopeStaff,
+	}
+	for input, expected := range cases {
+		scope, ok := utils.NormalizeRuleScope(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, expected, scope, input)
+	}
+
+	for _, input := range []string{"", "organization", "dept"} {
+		_, ok := utils.NormalizeRuleScope(input)
+		assert.False(t, ok, input)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupBudget creates a department with an annual budget of 120 USD and a software costing 6 USD a
+// seat per month, so that the second seat takes the department over budget
+func setupBudget(t *testing.T, enforcement string) (models.Department, models.Software) {
+	t.Helper()
+	department := models.Department{Name: "Engineering", AnnualBudget: 120, BudgetCurrency: "USD", BudgetEnforcement: enforcement}
+	assert.NoError(t, config.DB.Create(&department).Error)
+	software := models.Software{Name: "Figma", SeatPrice: 6, BillingPeriod: "monthly", Currency: "USD"}
+	assert.NoError(t, config.DB.Create(&software).Error)
+	return department, software
+}
+
+func TestRejectingDepartmentRefusesOverspend(t *testing.T) {
+	UseTestDB(t)
+	department, software := setupBudget(t, utils.BudgetReject)
+	first := createTestStaff(t, "first@shuttlers.co", department.ID, 0)
+	second := createTestStaff(t, "second@shuttlers.co", department.ID, 0)
+
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: first.ID, SoftwareID: software.ID, Source: "manual"}))
+	err := utils.CreateAssignment(&models.AssignedSoftware{StaffID: second.ID, SoftwareID: software.ID, Source: "manual"})
+	assert.ErrorIs(t, err, utils.ErrOverBudget)
+
+	var held int64
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ?", second.ID).Count(&held)
+	assert.Zero(t, held)
+}
+
+func TestWarningDepartmentAssignsAndAlerts(t *testing.T) {
+	UseTestDB(t)
+	department, software := setupBudget(t, utils.BudgetWarn)
+	first := createTestStaff(t, "first@shuttlers.co", department.ID, 0)
+	second := createTestStaff(t, "second@shuttlers.co", department.ID, 0)
+
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: first.ID, SoftwareID: software.ID, Source: "manual"}))
+	var alerts int64
+	config.DB.Model(&models.Reminder{}).Where("kind = ?", utils.ReminderBudgetOverspend).Count(&alerts)
+	assert.Zero(t, alerts, "within budget")
+
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: second.ID, SoftwareID: software.ID, Source: "manual"}))
+	config.DB.Model(&models.Reminder{}).Where("kind = ? AND department_id = ?", utils.ReminderBudgetOverspend, department.ID).Count(&alerts)
+	assert.Equal(t, int64(1), alerts)
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBundleScope(t *testing.T) {
+	for input, want := range map[string]string{
+		"organization": utils.ScopeOrganization,
+		"DEPARTMENT":   utils.ScopeDepartment,
+		"Team":         utils.ScopeTeam,
+	} {
+		scope, ok := utils.NormalizeBundleScope(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, scope)
+	}
+
+	// Bundles are not matched to single staff members; they are assigned to them directly
+	_, ok := utils.NormalizeBundleScope("Staff")
+	assert.False(t, ok)
+}
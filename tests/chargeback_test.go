@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatDaysInPeriod(t *testing.T) {
+	start, end, err := utils.ParseBillingPeriod("2025-06")
+	assert.NoError(t, err)
+
+	day := func(d int) time.Time { return start.AddDate(0, 0, d-1) }
+
+	logs := []models.SoftwareAssignmentLog{
+		// Staff 1 held software 1 before the period and left on June 11th
+		{StaffID: 1, SoftwareID: 1, Action: "Assigned", ChangedAt: start.AddDate(0, -2, 0)},
+		{StaffID: 1, SoftwareID: 1, Action: "Unassigned", ChangedAt: day(11)},
+		// Staff 2 joined on June 21st and still holds it
+		{StaffID: 2, SoftwareID: 1, Action: "Assigned", ChangedAt: day(21)},
+		// Staff 3 left before the period started
+		{StaffID: 3, SoftwareID: 1, Action: "Assigned", ChangedAt: start.AddDate(0, -3, 0)},
+		{StaffID: 3, SoftwareID: 1, Action: "Unassigned (Rule Deleted)", ChangedAt: start.AddDate(0, -1, 0)},
+	}
+	current := []models.AssignedSoftware{
+		{StaffID: 2, SoftwareID: 1, AssignedAt: day(21)},
+		// Staff 4 was assigned without a log entry
+		{StaffID: 4, SoftwareID: 1, AssignedAt: start.AddDate(-1, 0, 0)},
+	}
+
+	days := utils.SeatDaysInPeriod(start, end, logs, current)
+
+	assert.InDelta(t, 10, days[utils.StaffSoftware{StaffID: 1, SoftwareID: 1}], 0.01)
+	assert.InDelta(t, 10, days[utils.StaffSoftware{StaffID: 2, SoftwareID: 1}], 0.01)
+	assert.InDelta(t, 30, days[utils.StaffSoftware{StaffID: 4, SoftwareID: 1}], 0.01)
+	_, found := days[utils.StaffSoftware{StaffID: 3, SoftwareID: 1}]
+	assert.False(t, found)
+
+	_, _, err = utils.ParseBillingPeriod("June")
+	assert.Error(t, err)
+}
+
+func TestChargebackSkipsPendingAssignments(t *testing.T) {
+	UseTestDB(t)
+	start, _, _ := utils.ParseBillingPeriod("2025-06")
+	before := start.AddDate(0, -1, 0)
+	future := time.Now().AddDate(1, 0, 0)
+	engineering := createTestDepartment(t, "Engineering")
+	design := createTestDepartment(t, "Design")
+	software := models.Software{Name: "Figma", SeatPrice: 30, BillingPeriod: "monthly", Currency: "USD"}
+	assert.NoError(t, config.DB.Create(&software).Error)
+	active := createTestStaff(t, "engineer@shuttlers.co", engineering.ID, 0)
+	pending := createTestStaff(t, "designer@shuttlers.co", design.ID, 0)
+
+	assert.NoError(t, config.DB.Create(&models.AssignedSoftware{StaffID: active.ID, SoftwareID: software.ID, AssignedAt: before, Status: utils.AssignmentActive}).Error)
+	assert.NoError(t, config.DB.Create(&models.AssignedSoftware{StaffID: pending.ID, SoftwareID: software.ID, AssignedAt: before, StartsAt: &future, Status: utils.AssignmentPending}).Error)
+
+	lines, err := utils.BuildChargeback("2025-06")
+	assert.NoError(t, err)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, engineering.ID, lines[0].DepartmentID)
+		assert.InDelta(t, 30, lines[0].SeatDays, 0.01)
+		assert.InDelta(t, 30, lines[0].Cost, 0.01)
+	}
+}
+
+func TestChargebackKeepsCurrenciesApart(t *testing.T) {
+	UseTestDB(t)
+	start, _, _ := utils.ParseBillingPeriod("2025-06")
+	before := start.AddDate(0, -1, 0)
+	engineering := createTestDepartment(t, "Engineering")
+	software := models.Software{Name: "Miro", SeatPrice: 30, BillingPeriod: "monthly", Currency: "USD"}
+	assert.NoError(t, config.DB.Create(&software).Error)
+	plan := models.SoftwarePlan{SoftwareID: software.ID, Name: "Enterprise", SeatPrice: 20, BillingPeriod: "monthly", Currency: "EUR"}
+	assert.NoError(t, config.DB.Create(&plan).Error)
+	onSoftware := createTestStaff(t, "usd@shuttlers.co", engineering.ID, 0)
+	onPlan := createTestStaff(t, "eur@shuttlers.co", engineering.ID, 0)
+
+	assert.NoError(t, config.DB.Create(&models.AssignedSoftware{StaffID: onSoftware.ID, SoftwareID: software.ID, AssignedAt: before, Status: utils.AssignmentActive}).Error)
+	assert.NoError(t, config.DB.Create(&models.AssignedSoftware{StaffID: onPlan.ID, SoftwareID: software.ID, PlanID: &plan.ID, AssignedAt: before, Status: utils.AssignmentActive}).Error)
+
+	lines, err := utils.BuildChargeback("2025-06")
+	assert.NoError(t, err)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "EUR", lines[0].Currency)
+		assert.InDelta(t, 20, lines[0].Cost, 0.01)
+		assert.InDelta(t, 30, lines[0].SeatDays, 0.01)
+		assert.Equal(t, "USD", lines[1].Currency)
+		assert.InDelta(t, 30, lines[1].Cost, 0.01)
+		assert.InDelta(t, 30, lines[1].SeatDays, 0.01)
+	}
+}
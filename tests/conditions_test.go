@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConditionMatchesStaff(t *testing.T) {
+	engineer := models.Staff{
+		Email:          "ada@shuttlers.co",
+		Status:         "Active",
+		EmploymentType: "employee",
+		DepartmentID:   2,
+		Department:     models.Department{ID: 2, Name: "Engineering"},
+		TeamID:         5,
+		Team:           models.Team{ID: 5, Name: "Platform"},
+	}
+	contractor := engineer
+	contractor.Email = "bob@agency.com"
+	contractor.EmploymentType = "Contractor"
+
+	condition, err := utils.ParseCondition(`status == "Active" AND department == engineering AND email ends_with "@shuttlers.co" AND employment_type != "contractor"`)
+	assert.NoError(t, err)
+	assert.True(t, condition.Match(utils.StaffAttributes(engineer)))
+	assert.False(t, condition.Match(utils.StaffAttributes(contractor)))
+
+	cases := map[string]bool{
+		`team in ["Platform", "Data"]`:                             true,
+		`NOT (team_id = 5)`:                                        false,
+		`department_id == 3 || email contains "ADA"`:               true,
+		`first_name starts_with "x" or status != 'inactive'`:       true,
+		`(status == "Inactive" OR team == "Data") and team_id = 5`: false,
+	}
+	for expression, expected := range cases {
+		condition, err := utils.ParseCondition(expression)
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, condition.Match(utils.StaffAttributes(engineer)), expression)
+	}
+}
+
+func TestParseConditionRejectsInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		`salary > 100`,
+		`status == "Active" and team_id`,
+		`status ~ "Active"`,
+		`status == "Active`,
+		`(status == "Active"`,
+		`status == "Active" AND`,
+		`team in ["Platform" "Data"]`,
+	} {
+		_, err := utils.ParseCondition(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthlySeatCost(t *testing.T) {
+	assert.Equal(t, 10.0, utils.MonthlySeatCost(10, "monthly"))
+	assert.Equal(t, 10.0, utils.MonthlySeatCost(30, "quarterly"))
+	assert.Equal(t, 10.0, utils.MonthlySeatCost(120, "annual"))
+	assert.Equal(t, 10.0, utils.MonthlySeatCost(10, ""))
+}
+
+func TestBuildSpendReport(t *testing.T) {
+	rows := []models.SpendRow{
+		{DepartmentID: 1, Department: "Engineering", TeamID: 1, Team: "Backend", SoftwareID: 1, Software: "ClickUp", SeatPrice: 10, BillingPeriod: "monthly", Currency: "USD", Seats: 3},
+		{DepartmentID: 1, Department: "Engineering", TeamID: 2, Team: "Frontend", SoftwareID: 1, Software: "ClickUp", SeatPrice: 10, BillingPeriod: "monthly", Currency: "USD", Seats: 2},
+		{DepartmentID: 2, Department: "Operations", TeamID: 3, Team: "Logistics", SoftwareID: 2, Software: "Figma", SeatPrice: 120, BillingPeriod: "annual", Currency: "USD", Seats: 1},
+	}
+
+	report := utils.BuildSpendReport(rows)
+
+	assert.Len(t, report.ByDepartment, 2)
+	assert.Equal(t, "Engineering", report.ByDepartment[0].Name)
+	assert.Equal(t, 5, report.ByDepartment[0].Seats)
+	assert.Equal(t, 50.0, report.ByDepartment[0].MonthlyCost)
+	assert.Equal(t, 600.0, report.ByDepartment[0].AnnualCost)
+
+	assert.Len(t, report.ByTeam, 3)
+	assert.Len(t, report.BySoftware, 2)
+	assert.Equal(t, 10.0, report.BySoftware[1].MonthlyCost)
+	assert.Equal(t, 120.0, report.BySoftware[1].AnnualCost)
+}
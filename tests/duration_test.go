@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWithin(t *testing.T) {
+	cases := map[string]time.Duration{
+		"60d": 60 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"30":  30 * 24 * time.Hour,
+		"72h": 72 * time.Hour,
+	}
+	for input, expected := range cases {
+		d, err := utils.ParseWithin(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, d, input)
+	}
+
+	for _, input := range []string{"", "abc", "-5d", "d"} {
+		_, err := utils.ParseWithin(input)
+		assert.Error(t, err, input)
+	}
+}
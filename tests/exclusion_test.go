@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExclusionActive(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	tomorrow := now.AddDate(0, 0, 1)
+	yesterday := now.AddDate(0, 0, -1)
+
+	// Without an expiry the exclusion never lapses
+	assert.True(t, utils.ExclusionActive(models.SoftwareExclusion{}, now))
+
+	assert.True(t, utils.ExclusionActive(models.SoftwareExclusion{ExpiresAt: &tomorrow}, now))
+	assert.False(t, utils.ExclusionActive(models.SoftwareExclusion{ExpiresAt: &yesterday}, now))
+	assert.False(t, utils.ExclusionActive(models.SoftwareExclusion{ExpiresAt: &now}, now))
+}
+
+func TestStaffExclusionsAreListed(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	excluded := createTestSoftware(t, "Zoom", 0)
+	staff := createTestStaff(t, "contractor@shuttlers.co", 0, 0)
+	other := createTestStaff(t, "employee@shuttlers.co", 0, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: software.ID, Source: utils.SourceManual, AssignedAt: time.Now()}))
+	assert.NoError(t, config.DB.Create(&models.SoftwareExclusion{StaffID: staff.ID, SoftwareID: excluded.ID, Reason: "Contractors use the client's tooling"}).Error)
+	assert.NoError(t, config.DB.Create(&models.SoftwareExclusion{StaffID: other.ID, SoftwareID: excluded.ID}).Error)
+
+	// The assigned-software detail keeps its shape
+	w, _ := PerformRequest("GET", "/api/staff/"+itoa(staff.ID)+"/assigned-software/detail", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list []models.Software
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list, 1)
+
+	w, _ = PerformRequest("GET", "/api/staff/"+itoa(staff.ID)+"/exclusions", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var exclusions []models.SoftwareExclusion
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &exclusions))
+	if assert.Len(t, exclusions, 1) {
+		assert.Equal(t, excluded.ID, exclusions[0].SoftwareID)
+		assert.True(t, utils.ExclusionActive(exclusions[0], time.Now()))
+		if assert.NotNil(t, exclusions[0].Software) {
+			assert.Equal(t, "Zoom", exclusions[0].Software.Name)
+		}
+	}
+}
@@ -5,6 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/routes"
 )
 
 func PerformRequest(method, path string, body interface{}) (*httptest.ResponseRecorder, error) {
@@ -20,7 +27,44 @@ func PerformRequest(method, path string, body interface{}) (*httptest.ResponseRe
 	req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
 
+	if testRouter == nil {
+		initTestDB.Do(config.InitTestDB)
+		testRouter = routes.RegisterRoutes()
+	}
 	w := httptest.NewRecorder()
 	testRouter.ServeHTTP(w, req)
 	return w, nil
 }
+
+var initTestDB sync.Once
+
+// UseTestDB points config.DB at the in-memory test DB and empties it, for tests of DB-backed behaviour
+func UseTestDB(t *testing.T) {
+	t.Helper()
+	initTestDB.Do(config.InitTestDB)
+	config.ResetTestDB()
+}
+
+// createTestStaff inserts an active staff member with the given email into the test DB
+func createTestStaff(t *testing.T, email string, departmentID, teamID uint) models.StaffPlain {
+	t.Helper()
+	staff := models.StaffPlain{FirstName: "Test", LastName: "Staff", Email: email, Status: "Active", DepartmentID: departmentID, TeamID: teamID}
+	if err := config.DB.Create(&staff).Error; err != nil {
+		t.Fatalf("create staff: %v", err)
+	}
+	return staff
+}
+
+// createTestSoftware inserts a software with the given seat limit into the test DB
+func createTestSoftware(t *testing.T, name string, seatLimit int) models.Software {
+	t.Helper()
+	software := models.Software{Name: name, SeatLimit: seatLimit, BillingPeriod: "monthly", Currency: "USD"}
+	if err := config.DB.Create(&software).Error; err != nil {
+		t.Fatalf("create software: %v", err)
+	}
+	return software
+}
+
+func itoa(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
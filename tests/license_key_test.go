@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignmentsNeverShareALicenseKey(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Office", 0)
+	key := models.LicenseKey{SoftwareID: software.ID, EncryptedKey: "sealed", KeyHint: "****-7QX9"}
+	assert.NoError(t, config.DB.Create(&key).Error)
+	first := createTestStaff(t, "first@shuttlers.co", 0, 0)
+	second := createTestStaff(t, "second@shuttlers.co", 0, 0)
+
+	assignment := models.AssignedSoftware{StaffID: first.ID, SoftwareID: software.ID, Source: "manual"}
+	assert.NoError(t, utils.CreateAssignment(&assignment))
+	err := utils.CreateAssignment(&models.AssignedSoftware{StaffID: second.ID, SoftwareID: software.ID, Source: "manual"})
+	assert.ErrorIs(t, err, utils.ErrNoLicenseKeyAvailable)
+
+	assert.NoError(t, config.DB.First(&key, key.ID).Error)
+	if assert.NotNil(t, key.AssignedSoftwareID) {
+		assert.Equal(t, assignment.ID, *key.AssignedSoftwareID)
+	}
+	var held int64
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ?", second.ID).Count(&held)
+	assert.Zero(t, held, "the assignment without a key is rolled back")
+
+	// Revoking returns the key to the pool for the next assignment
+	assert.NoError(t, utils.RevokeAssignment(assignment, utils.ActionUnassigned))
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: second.ID, SoftwareID: software.ID, Source: "manual"}))
+}
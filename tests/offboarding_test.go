@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeHandling(t *testing.T) {
+	handling, ok := utils.NormalizeHandling("")
+	assert.True(t, ok)
+	assert.Equal(t, utils.HandleRevoke, handling, "manual assignments are revoked by default")
+
+	handling, ok = utils.NormalizeHandling(" Transfer ")
+	assert.True(t, ok)
+	assert.Equal(t, utils.HandleTransfer, handling)
+
+	handling, ok = utils.NormalizeHandling("KEEP")
+	assert.True(t, ok)
+	assert.Equal(t, utils.HandleKeep, handling)
+
+	_, ok = utils.NormalizeHandling("archive")
+	assert.False(t, ok)
+}
+
+func TestReconcileLeavesKeptSoftwareWithLeaver(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "QuickBooks", 0)
+	leaver := createTestStaff(t, "accountant@shuttlers.co", 0, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: leaver.ID, SoftwareID: software.ID, Source: utils.SourceManual}))
+
+	offboarding := models.Offboarding{StaffID: leaver.ID, EffectiveDate: time.Now(), ManualHandling: utils.HandleKeep, Reason: "Closes the Q3 accounts"}
+	assert.NoError(t, utils.ScheduleOffboarding(&offboarding, nil))
+	assert.Equal(t, utils.OffboardingCompleted, offboarding.Status)
+
+	report, err := utils.Reconcile(leaver.ID, true)
+	assert.NoError(t, err)
+	assert.Zero(t, report.InactiveStaff)
+	assert.Equal(t, int64(1), heldSoftware(leaver.ID, software.ID), "kept software survives reconciliation")
+}
+
+func TestOffboardingIsHeldWhileOwnershipIsUnbacked(t *testing.T) {
+	UseTestDB(t)
+	leaver := createTestStaff(t, "owner@shuttlers.co", 0, 0)
+	offboarding := models.Offboarding{StaffID: leaver.ID, EffectiveDate: time.Now().AddDate(0, 0, 7), ManualHandling: utils.HandleRevoke}
+	assert.NoError(t, utils.ScheduleOffboarding(&offboarding, nil))
+	// Made sole owner after the offboarding was scheduled
+	createOwnedSoftware(t, "Figma", leaver.ID, nil)
+
+	assert.ErrorIs(t, utils.ExecuteOffboarding(&offboarding), utils.ErrUnbackedOwnership)
+	var stored models.Offboarding
+	config.DB.First(&stored, offboarding.ID)
+	assert.Equal(t, utils.OffboardingScheduled, stored.Status)
+	var staff models.StaffPlain
+	config.DB.First(&staff, leaver.ID)
+	assert.Equal(t, "Active", staff.Status, "a held offboarding changes nothing")
+
+	stored.Forced = true
+	assert.NoError(t, utils.ExecuteOffboarding(&stored))
+	assert.Equal(t, utils.OffboardingCompleted, stored.Status)
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemResolved(t *testing.T) {
+	assert.True(t, utils.ItemResolved(utils.ItemProvisioned))
+	assert.True(t, utils.ItemResolved(utils.ItemDone))
+	assert.True(t, utils.ItemResolved(utils.ItemSkipped))
+
+	// Pending and failed items keep an onboarding open
+	assert.False(t, utils.ItemResolved(utils.ItemPending))
+	assert.False(t, utils.ItemResolved(utils.ItemFailed))
+}
+
+func TestCreateStaffReturnsOnboarding(t *testing.T) {
+	UseTestDB(t)
+	w, _ := PerformRequest("POST", "/api/staff/with-software", map[string]interface{}{
+		"first_name": "Jane", "last_name": "Roe", "email": "jane.roe@shuttlers.co",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.StaffWithOnboarding
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.Onboarding) {
+		assert.NotZero(t, response.Onboarding.ID)
+	}
+	assert.Empty(t, response.OnboardingError)
+}
+
+func TestCreateStaffReportsFailedOnboarding(t *testing.T) {
+	UseTestDB(t)
+	config.DB.Exec("CREATE TRIGGER fail_onboarding BEFORE INSERT ON onboardings BEGIN SELECT RAISE(ABORT, 'onboarding refused'); END")
+	defer config.DB.Exec("DROP TRIGGER fail_onboarding")
+
+	w, _ := PerformRequest("POST", "/api/staff/with-software", map[string]interface{}{
+		"first_name": "Jane", "last_name": "Roe", "email": "jane.roe@shuttlers.co",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.StaffWithOnboarding
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotZero(t, response.ID, "the staff member is created")
+	assert.Nil(t, response.Onboarding, "an unsaved onboarding is not returned")
+	assert.Contains(t, response.OnboardingError, "onboarding refused")
+}
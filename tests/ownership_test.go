@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createOwnedSoftware(t *testing.T, name string, ownerID uint, backupOwnerID *uint) models.Software {
+	t.Helper()
+	software := models.Software{Name: name, BillingPeriod: "monthly", Currency: "USD", OwnerID: &ownerID, BackupOwnerID: backupOwnerID}
+	if err := config.DB.Create(&software).Error; err != nil {
+		t.Fatalf("create software: %v", err)
+	}
+	return software
+}
+
+func TestUnbackedSoftware(t *testing.T) {
+	UseTestDB(t)
+	owner := createTestStaff(t, "owner@shuttlers.co", 0, 0)
+	backup := createTestStaff(t, "backup@shuttlers.co", 0, 0)
+	leaver := createTestStaff(t, "leaver@shuttlers.co", 0, 0)
+	config.DB.Model(&models.StaffPlain{}).Where("id = ?", leaver.ID).Update("status", "Inactive")
+
+	createOwnedSoftware(t, "Figma", owner.ID, nil)
+	createOwnedSoftware(t, "Miro", owner.ID, &backup.ID)
+	createOwnedSoftware(t, "Notion", owner.ID, &leaver.ID)
+
+	unbacked, err := utils.UnbackedSoftware(owner.ID)
+	assert.NoError(t, err)
+	var names []string
+	for _, sw := range unbacked {
+		names = append(names, sw.Name)
+	}
+	assert.Equal(t, []string{"Figma", "Notion"}, names, "an inactive backup owner does not count")
+}
+
+func TestOffboardingSoleOwnerNeedsForce(t *testing.T) {
+	UseTestDB(t)
+	owner := createTestStaff(t, "owner@shuttlers.co", 0, 0)
+	software := createOwnedSoftware(t, "Figma", owner.ID, nil)
+
+	w, _ := PerformRequest("PUT", "/api/staff/"+itoa(owner.ID)+"/offboard", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var staff models.StaffPlain
+	config.DB.First(&staff, owner.ID)
+	assert.Equal(t, "Active", staff.Status, "a refused offboarding changes nothing")
+
+	w, _ = PerformRequest("PUT", "/api/staff/"+itoa(owner.ID)+"/offboard?force=true", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	config.DB.First(&staff, owner.ID)
+	assert.Equal(t, "inactive", staff.Status)
+	// The software keeps its leaver as owner until it is reassigned
+	config.DB.First(&software, software.ID)
+	assert.Equal(t, owner.ID, *software.OwnerID)
+}
+
+func TestOffboardingHandsOwnershipToBackup(t *testing.T) {
+	UseTestDB(t)
+	owner := createTestStaff(t, "owner@shuttlers.co", 0, 0)
+	backup := createTestStaff(t, "backup@shuttlers.co", 0, 0)
+	other := createTestStaff(t, "other@shuttlers.co", 0, 0)
+	owned := createOwnedSoftware(t, "Figma", owner.ID, &backup.ID)
+	backedUp := createOwnedSoftware(t, "Miro", other.ID, &owner.ID)
+
+	w, _ := PerformRequest("PUT", "/api/staff/"+itoa(owner.ID)+"/offboard", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	config.DB.First(&owned, owned.ID)
+	assert.Equal(t, backup.ID, *owned.OwnerID)
+	assert.Nil(t, owned.BackupOwnerID)
+	config.DB.First(&backedUp, backedUp.ID)
+	assert.Equal(t, other.ID, *backedUp.OwnerID)
+	assert.Nil(t, backedUp.BackupOwnerID, "the leaver no longer backs up other software")
+}
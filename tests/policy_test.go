@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderByPrerequisites(t *testing.T) {
+	policies := []models.SoftwarePolicy{
+		{Kind: utils.PolicyRequires, SoftwareID: 7, RelatedSoftwareID: 6}, // Jira Service Management needs Jira
+		{Kind: utils.PolicyRequires, SoftwareID: 6, RelatedSoftwareID: 5},
+		{Kind: utils.PolicyConflict, SoftwareID: 1, RelatedSoftwareID: 2},
+	}
+
+	assert.Equal(t, []uint{1, 5, 6, 7, 2}, utils.OrderByPrerequisites([]uint{1, 7, 6, 5, 2}, policies))
+
+	// A prerequisite outside the list does not change the order
+	assert.Equal(t, []uint{7, 1}, utils.OrderByPrerequisites([]uint{7, 1}, policies))
+
+	// Conflicts never reorder
+	assert.Equal(t, []uint{2, 1}, utils.OrderByPrerequisites([]uint{2, 1}, policies))
+
+	// Cyclic requirements terminate
+	cyclic := []models.SoftwarePolicy{
+		{Kind: utils.PolicyRequires, SoftwareID: 1, RelatedSoftwareID: 2},
+		{Kind: utils.PolicyRequires, SoftwareID: 2, RelatedSoftwareID: 1},
+	}
+	assert.ElementsMatch(t, []uint{1, 2}, utils.OrderByPrerequisites([]uint{1, 2}, cyclic))
+}
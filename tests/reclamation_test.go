@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReclamationStage(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	policy := models.ReclamationPolicy{IdleDays: 60, WarningDays: 7, Enabled: true}
+
+	// Not yet within the warning window
+	assert.Equal(t, utils.ReclaimNone, utils.ReclamationStage(policy, daysAgo(50), nil, now))
+
+	// Within the warning window and not warned yet
+	assert.Equal(t, utils.ReclaimWarn, utils.ReclamationStage(policy, daysAgo(54), nil, now))
+
+	// Past the idle threshold but never warned: warn first rather than revoke
+	assert.Equal(t, utils.ReclaimWarn, utils.ReclamationStage(policy, daysAgo(90), nil, now))
+
+	// Warned recently: wait for the warning period to run out
+	warned := daysAgo(3)
+	assert.Equal(t, utils.ReclaimNone, utils.ReclamationStage(policy, daysAgo(90), &warned, now))
+
+	// Warned long enough ago and idle past the threshold
+	warned = daysAgo(7)
+	assert.Equal(t, utils.ReclaimRevoke, utils.ReclamationStage(policy, daysAgo(60), &warned, now))
+
+	// Disabled policies never act
+	policy.Enabled = false
+	assert.Equal(t, utils.ReclaimNone, utils.ReclamationStage(policy, daysAgo(90), &warned, now))
+}
+
+func TestReclaimDate(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	policy := models.ReclamationPolicy{IdleDays: 60, WarningDays: 7, Enabled: true}
+
+	// Idle threshold is further away than the warning period
+	since := now.AddDate(0, 0, -50)
+	assert.Equal(t, since.AddDate(0, 0, 60), utils.ReclaimDate(policy, since, nil, now))
+
+	// Long idle but not warned yet: the warning period still applies
+	since = now.AddDate(0, 0, -90)
+	assert.Equal(t, now.AddDate(0, 0, 7), utils.ReclaimDate(policy, since, nil, now))
+
+	warned := now.AddDate(0, 0, -2)
+	assert.Equal(t, warned.AddDate(0, 0, 7), utils.ReclaimDate(policy, since, &warned, now))
+
+	assigned := now.AddDate(0, 0, -10)
+	used := now.AddDate(0, 0, -1)
+	assert.Equal(t, assigned, utils.IdleSince(models.AssignedSoftware{AssignedAt: assigned}))
+	assert.Equal(t, used, utils.IdleSince(models.AssignedSoftware{AssignedAt: assigned, LastUsedAt: &used}))
+}
+
+func TestReclaimedSeatStaysGone(t *testing.T) {
+	UseTestDB(t)
+	department := createTestDepartment(t, "Design")
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", department.ID, 0)
+	config.DB.Create(&models.SoftwareDepartmentMatch{SoftwareID: software.ID, DepartmentID: department.ID})
+	assert.NoError(t, utils.AutoAssignSoftwareToStaff(staff.ID, department.ID, 0))
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ?", staff.ID).Update("assigned_at", time.Now().AddDate(0, 0, -100))
+	config.DB.Create(&models.ReclamationPolicy{SoftwareID: software.ID, IdleDays: 60, WarningDays: 7, Enabled: true})
+
+	// Warned on the first run, reclaimed once the warning is old enough
+	assert.NoError(t, utils.ProcessSeatReclamation())
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID))
+	config.DB.Model(&models.Reminder{}).Where("kind = ?", utils.ReminderIdleSeat).Update("fired_at", time.Now().AddDate(0, 0, -10))
+	assert.NoError(t, utils.ProcessSeatReclamation())
+	assert.Zero(t, heldSoftware(staff.ID, software.ID))
+
+	var exclusion models.SoftwareExclusion
+	assert.NoError(t, config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, software.ID).First(&exclusion).Error)
+	assert.Equal(t, "Reclaimed: idle 100 days", exclusion.Reason)
+
+	report, err := utils.Reconcile(staff.ID, true)
+	assert.NoError(t, err)
+	assert.Zero(t, report.Missing)
+	assert.Zero(t, heldSoftware(staff.ID, software.ID), "reconciliation does not give the seat back")
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestDepartment(t *testing.T, name string) models.Department {
+	t.Helper()
+	department := models.Department{Name: name}
+	if err := config.DB.Create(&department).Error; err != nil {
+		t.Fatalf("create department: %v", err)
+	}
+	return department
+}
+
+func heldSoftware(staffID, softwareID uint) int64 {
+	var held int64
+	config.DB.Model(&models.AssignedSoftware{}).Where("staff_id = ? AND software_id = ?", staffID, softwareID).Count(&held)
+	return held
+}
+
+func TestReconcileReportsThenCreatesMissingAssignment(t *testing.T) {
+	UseTestDB(t)
+	department := createTestDepartment(t, "Design")
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", department.ID, 0)
+	// The match is inserted directly, so nothing is assigned yet
+	config.DB.Create(&models.SoftwareDepartmentMatch{SoftwareID: software.ID, DepartmentID: department.ID})
+
+	report, err := utils.Reconcile(staff.ID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Missing)
+	assert.Equal(t, 0, report.Fixed)
+	assert.Zero(t, heldSoftware(staff.ID, software.ID), "a dry run changes nothing")
+
+	report, err = utils.Reconcile(staff.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Fixed)
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, software.ID))
+
+	report, err = utils.Reconcile(staff.ID, false)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Items, "no drift is left once applied")
+}
+
+func TestReconcileRevokesStaleAutoAssignmentOnly(t *testing.T) {
+	UseTestDB(t)
+	department := createTestDepartment(t, "Design")
+	matched := createTestSoftware(t, "Figma", 0)
+	manual := createTestSoftware(t, "Miro", 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", department.ID, 0)
+	// Granted by a department match that no longer exists
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: matched.ID, Source: utils.SourceDepartment}))
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: manual.ID, Source: "manual"}))
+
+	report, err := utils.Reconcile(staff.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Stale)
+	assert.Zero(t, heldSoftware(staff.ID, matched.ID))
+	assert.Equal(t, int64(1), heldSoftware(staff.ID, manual.ID), "manual assignments are never stale")
+}
+
+func TestReconcileRevokesInactiveStaffAssignments(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 0)
+	staff := createTestStaff(t, "leaver@shuttlers.co", 0, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: software.ID, Source: "manual"}))
+	config.DB.Model(&models.StaffPlain{}).Where("id = ?", staff.ID).Update("status", "Inactive")
+
+	report, err := utils.Reconcile(staff.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.InactiveStaff)
+	assert.Equal(t, 1, report.Fixed)
+	assert.Zero(t, heldSoftware(staff.ID, software.ID))
+}
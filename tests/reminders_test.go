@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestContract inserts a contract of software ending endsIn from now with the given notice period
+func createTestContract(t *testing.T, number string, software models.Software, endsIn time.Duration, noticeDays int) models.Contract {
+	t.Helper()
+	vendor := models.Vendor{Name: "Vendor " + number}
+	assert.NoError(t, config.DB.Create(&vendor).Error)
+	contract := models.Contract{
+		VendorID:         vendor.ID,
+		SoftwareID:       software.ID,
+		ContractNumber:   number,
+		StartDate:        time.Now().AddDate(-1, 0, 0),
+		EndDate:          time.Now().Add(endsIn),
+		NoticePeriodDays: noticeDays,
+	}
+	assert.NoError(t, config.DB.Omit("Vendor", "Software").Create(&contract).Error)
+	return contract
+}
+
+func contractReminders(t *testing.T, contractID uint, kind string) []models.Reminder {
+	t.Helper()
+	var reminders []models.Reminder
+	assert.NoError(t, config.DB.Where("contract_id = ? AND kind = ?", contractID, kind).Find(&reminders).Error)
+	return reminders
+}
+
+func TestContractRemindersFireWithinLeadWindowOnce(t *testing.T) {
+	UseTestDB(t)
+	t.Setenv("REMINDER_LEAD_DAYS", "30")
+	software := createTestSoftware(t, "ClickUp", 0)
+	day := 24 * time.Hour
+
+	// Notice deadline in 10 days, end in 40 days
+	soon := createTestContract(t, "CU-1", software, 40*day, 30)
+	// Notice deadline in 60 days
+	later := createTestContract(t, "CU-2", software, 90*day, 30)
+
+	assert.NoError(t, utils.ProcessContractReminders())
+	assert.NoError(t, utils.ProcessContractReminders())
+
+	assert.Len(t, contractReminders(t, soon.ID, utils.ReminderContractNotice), 1, "deduplicated across runs")
+	assert.Empty(t, contractReminders(t, soon.ID, utils.ReminderContractEnd), "end date is outside the window")
+	assert.Empty(t, contractReminders(t, later.ID, utils.ReminderContractNotice))
+}
+
+func TestOverdueNoticeDeadlineStillFires(t *testing.T) {
+	UseTestDB(t)
+	t.Setenv("REMINDER_LEAD_DAYS", "30")
+	software := createTestSoftware(t, "ClickUp", 0)
+	day := 24 * time.Hour
+
+	// The notice deadline passed five days ago, e.g. while the job was down
+	missed := createTestContract(t, "CU-3", software, 25*day, 30)
+
+	assert.NoError(t, utils.ProcessContractReminders())
+	assert.NoError(t, utils.ProcessContractReminders())
+
+	notices := contractReminders(t, missed.ID, utils.ReminderContractNotice)
+	if assert.Len(t, notices, 1) {
+		assert.Contains(t, notices[0].Message, "passed")
+	}
+	assert.Len(t, contractReminders(t, missed.ID, utils.ReminderContractEnd), 1)
+}
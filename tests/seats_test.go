@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAssignmentEnforcesSeatLimit(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 1)
+	first := createTestStaff(t, "first@shuttlers.co", 0, 0)
+	second := createTestStaff(t, "second@shuttlers.co", 0, 0)
+
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: first.ID, SoftwareID: software.ID, Source: "manual"}))
+	err := utils.CreateAssignment(&models.AssignedSoftware{StaffID: second.ID, SoftwareID: software.ID, Source: "manual"})
+	assert.ErrorIs(t, err, utils.ErrNoSeatsAvailable)
+
+	usage, err := utils.GetSeatUsage(software.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, usage.Used)
+	assert.Equal(t, 0, usage.Free)
+}
+
+func TestPendingAssignmentTakesNoSeat(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Figma", 1)
+	starter := createTestStaff(t, "starter@shuttlers.co", 0, 0)
+	current := createTestStaff(t, "current@shuttlers.co", 0, 0)
+
+	startsAt := time.Now().AddDate(0, 0, 14)
+	pending := models.AssignedSoftware{StaffID: starter.ID, SoftwareID: software.ID, Source: "manual", StartsAt: &startsAt}
+	assert.NoError(t, utils.CreateAssignment(&pending))
+	assert.Equal(t, utils.AssignmentPending, pending.Status)
+
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: current.ID, SoftwareID: software.ID, Source: "manual"}))
+
+	// The seat is gone by the time the pending assignment would activate
+	assert.ErrorIs(t, utils.ActivateAssignment(pending), utils.ErrNoSeatsAvailable)
+}
+
+func TestUnlimitedSoftwareHasNoSeatCap(t *testing.T) {
+	UseTestDB(t)
+	software := createTestSoftware(t, "Slack", 0)
+	for _, email := range []string{"a@shuttlers.co", "b@shuttlers.co", "c@shuttlers.co"} {
+		staff := createTestStaff(t, email, 0, 0)
+		assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: staff.ID, SoftwareID: software.ID, Source: "manual"}))
+	}
+}
+
+func TestUpdateCannotMoveASeat(t *testing.T) {
+	UseTestDB(t)
+	full := createTestSoftware(t, "Figma", 1)
+	other := createTestSoftware(t, "Miro", 0)
+	holder := createTestStaff(t, "holder@shuttlers.co", 0, 0)
+	staff := createTestStaff(t, "designer@shuttlers.co", 0, 0)
+	assert.NoError(t, utils.CreateAssignment(&models.AssignedSoftware{StaffID: holder.ID, SoftwareID: full.ID, Source: "manual"}))
+	record := models.AssignedSoftware{StaffID: staff.ID, SoftwareID: other.ID, Source: "manual"}
+	assert.NoError(t, utils.CreateAssignment(&record))
+
+	for _, body := range []map[string]interface{}{
+		{"software_id": full.ID},
+		{"staff_id": holder.ID},
+	} {
+		w, _ := PerformRequest("PUT", "/api/assigned-software/"+itoa(record.ID), body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	var stored models.AssignedSoftware
+	config.DB.First(&stored, record.ID)
+	assert.Equal(t, staff.ID, stored.StaffID)
+	assert.Equal(t, other.ID, stored.SoftwareID)
+	assert.Equal(t, int64(1), heldSoftware(holder.ID, full.ID))
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"software_management/config"
+	"software_management/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftwarePricingIsValidated(t *testing.T) {
+	UseTestDB(t)
+
+	w, _ := PerformRequest("POST", "/api/software", map[string]interface{}{"name": "Figma", "billing_period": "weekly"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = PerformRequest("POST", "/api/software", map[string]interface{}{"name": "Figma", "seat_price": -5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = PerformRequest("POST", "/api/software", map[string]interface{}{"name": "Figma", "seat_price": 15, "billing_period": "Annual", "currency": "eur"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var software models.Software
+	assert.NoError(t, config.DB.Where("name = ?", "Figma").First(&software).Error)
+	assert.Equal(t, "annual", software.BillingPeriod)
+	assert.Equal(t, "EUR", software.Currency)
+}
+
+func TestUpdateSoftwareKeepsOmittedPricing(t *testing.T) {
+	UseTestDB(t)
+	software := models.Software{Name: "Figma", SeatLimit: 10, SeatPrice: 15, BillingPeriod: "annual", Currency: "EUR"}
+	assert.NoError(t, config.DB.Create(&software).Error)
+
+	w, _ := PerformRequest("PUT", "/api/software/"+itoa(software.ID), map[string]interface{}{"name": "Figma", "description": "Design"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.Software
+	assert.NoError(t, config.DB.First(&updated, software.ID).Error)
+	assert.Equal(t, 10, updated.SeatLimit)
+	assert.Equal(t, 15.0, updated.SeatPrice)
+	assert.Equal(t, "annual", updated.BillingPeriod)
+	assert.Equal(t, "EUR", updated.Currency)
+
+	w, _ = PerformRequest("PUT", "/api/software/"+itoa(software.ID), map[string]interface{}{"name": "Figma", "billing_period": "fortnightly"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
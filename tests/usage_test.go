@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUsageRecordsJSONL(t *testing.T) {
+	input := `{"staff_email":"ada@shuttlers.co","software":"Figma","timestamp":"2025-06-01T10:00:00Z","event_type":"login"}
+
+not json
+{"staff_id":4,"software_id":2,"timestamp":"1717236000"}
+`
+	records, lineErrors, err := utils.ParseUsageRecords(strings.NewReader(input), utils.UsageFormatJSONL)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Len(t, lineErrors, 1)
+	assert.Equal(t, 3, lineErrors[0].Line)
+
+	assert.Equal(t, "ada@shuttlers.co", records[0].StaffEmail)
+	assert.Equal(t, "Figma", records[0].Software)
+	assert.Equal(t, 1, records[0].Line)
+	assert.Equal(t, uint(4), records[1].StaffID)
+	assert.Equal(t, uint(2), records[1].SoftwareID)
+	assert.Equal(t, 4, records[1].Line)
+}
+
+func TestParseUsageRecordsCSV(t *testing.T) {
+	input := "Email,Software_Name,Last_Seen,Event\nada@shuttlers.co,Slack,2025-06-01 08:30:00,active\nbob@shuttlers.co,Slack,2025-06-02,\n"
+	records, lineErrors, err := utils.ParseUsageRecords(strings.NewReader(input), utils.UsageFormatCSV)
+	assert.NoError(t, err)
+	assert.Empty(t, lineErrors)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "ada@shuttlers.co", records[0].StaffEmail)
+	assert.Equal(t, "Slack", records[0].Software)
+	assert.Equal(t, "2025-06-01 08:30:00", records[0].Timestamp)
+	assert.Equal(t, "active", records[0].EventType)
+	assert.Equal(t, 3, records[1].Line)
+
+	_, _, err = utils.ParseUsageRecords(strings.NewReader("email,software\nada@shuttlers.co,Slack\n"), utils.UsageFormatCSV)
+	assert.Error(t, err)
+
+	_, _, err = utils.ParseUsageRecords(strings.NewReader(""), "xml")
+	assert.Error(t, err)
+}
+
+func TestParseUsageTime(t *testing.T) {
+	expected := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	for _, input := range []string{"2025-06-01T10:00:00Z", "2025-06-01T10:00:00", "2025-06-01 10:00:00", "1748772000"} {
+		parsed, err := utils.ParseUsageTime(input)
+		assert.NoError(t, err, input)
+		assert.True(t, expected.Equal(parsed), input)
+	}
+
+	_, err := utils.ParseUsageTime("yesterday")
+	assert.Error(t, err)
+}
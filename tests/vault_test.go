@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"testing"
+
+	"software_management/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultRoundTrip(t *testing.T) {
+	t.Setenv("LICENSE_VAULT_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+
+	encrypted, err := utils.EncryptSecret("ABCDE-12345-FGHIJ-67890")
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, "ABCDE")
+
+	again, err := utils.EncryptSecret("ABCDE-12345-FGHIJ-67890")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "nonce must differ per encryption")
+
+	plaintext, err := utils.DecryptSecret(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "ABCDE-12345-FGHIJ-67890", plaintext)
+}
+
+func TestVaultKeyMissing(t *testing.T) {
+	t.Setenv("LICENSE_VAULT_KEY", "")
+
+	_, err := utils.EncryptSecret("secret")
+	assert.ErrorIs(t, err, utils.ErrVaultKeyMissing)
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// Constants for access request statuses
+const (
+	RequestPending   = "pending"
+	RequestApproved  = "approved"
+	RequestRejected  = "rejected"
+	RequestCancelled = "cancelled"
+)
+
+// ErrRequestNotPending is returned when deciding or cancelling a request that is no longer pending
+var ErrRequestNotPending = errors.New("access request is no longer pending")
+
+// ApproveAccessRequest approves a pending request and, in the same transaction, assigns the software
+// to the requester and logs the assignment as done by the approver. A requester who got the software
+// in the meantime keeps their assignment with a manual grant added. When the assignment cannot be
+// made, e.g. for lack of seats, nothing is written and the request stays pending.
+func ApproveAccessRequest(request *models.AccessRequest, approverID uint, note string) error {
+	now := time.Now()
+	var record models.AssignedSoftware
+	var overBudget *models.Department
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := decideAccessRequest(tx, request.ID, RequestApproved, map[string]interface{}{
+			"approver_id":   approverID,
+			"decision_note": note,
+			"decided_at":    now,
+		}); err != nil {
+			return err
+		}
+
+		var existing models.AssignedSoftware
+		if err := tx.Where("staff_id = ? AND software_id = ?", request.StaffID, request.SoftwareID).
+			First(&existing).Error; err == nil {
+			record = existing
+			grant := models.AssignmentGrant{SourceType: SourceManual, PlanID: existing.PlanID}
+			if err := addGrant(tx, existing.ID, grant); err != nil {
+				return err
+			}
+		} else {
+			record = models.AssignedSoftware{
+				StaffID:    request.StaffID,
+				SoftwareID: request.SoftwareID,
+				PlanID:     request.PlanID,
+				AssignedAt: now,
+				Source:     SourceManual,
+			}
+			var err error
+			if overBudget, err = createAssignment(tx, &record); err != nil {
+				return err
+			}
+			if err := tx.Create(&models.SoftwareAssignmentLog{
+				StaffID:    record.StaffID,
+				SoftwareID: record.SoftwareID,
+				Action:     ActionAssigned,
+				ChangedBy:  approverID,
+				ChangedAt:  now,
+				UpdatedAt:  now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.AccessRequest{}).Where("id = ?", request.ID).
+			Update("assigned_software_id", record.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+	if overBudget != nil {
+		alertOverBudget(*overBudget, record)
+	}
+	return config.DB.First(request, request.ID).Error
+}
+
+// RejectAccessRequest rejects a pending request
+func RejectAccessRequest(request *models.AccessRequest, approverID uint, note string) error {
+	if err := decideAccessRequest(config.DB, request.ID, RequestRejected, map[string]interface{}{
+		"approver_id":   approverID,
+		"decision_note": note,
+		"decided_at":    time.Now(),
+	}); err != nil {
+		return err
+	}
+	return config.DB.First(request, request.ID).Error
+}
+
+// CancelAccessRequest withdraws a pending request on behalf of its requester
+func CancelAccessRequest(request *models.AccessRequest) error {
+	if err := decideAccessRequest(config.DB, request.ID, RequestCancelled, nil); err != nil {
+		return err
+	}
+	return config.DB.First(request, request.ID).Error
+}
+
+// decideAccessRequest moves a request out of pending, failing with ErrRequestNotPending when it was
+// decided or cancelled already, including by a concurrent call
+func decideAccessRequest(db *gorm.DB, requestID uint, status string, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": status}
+	for column, value := range fields {
+		updates[column] = value
+	}
+	result := db.Model(&models.AccessRequest{}).
+		Where("id = ? AND status = ?", requestID, RequestPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRequestNotPending
+	}
+	return nil
+}
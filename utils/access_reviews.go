@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// Constants for access review campaigns
+const (
+	CampaignOpen      = "open"
+	CampaignCompleted = "completed"
+
+	ReviewByManager = "manager"
+	ReviewByOwner   = "owner"
+
+	ReviewKeep   = "keep"
+	ReviewRevoke = "revoke"
+
+	// ScopeSoftware is the scope of a campaign reviewing every holder of one software
+	ScopeSoftware = "Software"
+)
+
+var (
+	// ErrCampaignCompleted is returned when deciding on an item of a completed campaign
+	ErrCampaignCompleted = errors.New("access review campaign is already completed")
+	// ErrItemDecided is returned when deciding on an item that already has a decision
+	ErrItemDecided = errors.New("review item has already been decided")
+)
+
+// NormalizeReviewScope maps a campaign scope in any letter case onto its canonical spelling
+func NormalizeReviewScope(scope string) (string, bool) {
+	for _, known := range []string{ScopeOrganization, ScopeDepartment, ScopeSoftware} {
+		if strings.EqualFold(scope, known) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// ChooseReviewer picks who reviews a staff member's access to a software. The campaign's preferred
+// reviewer comes first, the manager or the owner, then the other one, then the backup owner. Nobody
+// reviews their own access; nil means no reviewer could be found.
+func ChooseReviewer(mode string, staffID uint, managerID, ownerID, backupOwnerID *uint) *uint {
+	candidates := []*uint{managerID, ownerID, backupOwnerID}
+	if mode == ReviewByOwner {
+		candidates = []*uint{ownerID, backupOwnerID, managerID}
+	}
+	for _, candidate := range candidates {
+		if candidate != nil && *candidate != staffID {
+			reviewer := *candidate
+			return &reviewer
+		}
+	}
+	return nil
+}
+
+// OpenAccessReview creates a campaign and snapshots the active assignments in its scope into review
+// items, each routed to a reviewer with ChooseReviewer
+func OpenAccessReview(campaign *models.AccessReviewCampaign) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		campaign.Status = CampaignOpen
+		campaign.Items = nil
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.AssignedSoftware{}).Where("assigned_software.status = ?", AssignmentActive)
+		switch campaign.ScopeType {
+		case ScopeDepartment:
+			query = query.Joins("JOIN staff ON staff.id = assigned_software.staff_id").
+				Where("staff.department_id = ?", campaign.ScopeID)
+		case ScopeSoftware:
+			query = query.Where("assigned_software.software_id = ?", campaign.ScopeID)
+		}
+		var assignments []models.AssignedSoftware
+		if err := query.Order("assigned_software.id").Find(&assignments).Error; err != nil {
+			return err
+		}
+		if len(assignments) == 0 {
+			return nil
+		}
+
+		var staffList []models.StaffPlain
+		if err := tx.Select("id", "manager_id").Find(&staffList).Error; err != nil {
+			return err
+		}
+		managers := make(map[uint]*uint, len(staffList))
+		for _, staff := range staffList {
+			managers[staff.ID] = staff.ManagerID
+		}
+		var softwareList []models.Software
+		if err := tx.Select("id", "owner_id", "backup_owner_id").Find(&softwareList).Error; err != nil {
+			return err
+		}
+		owners := make(map[uint]models.Software, len(softwareList))
+		for _, sw := range softwareList {
+			owners[sw.ID] = sw
+		}
+
+		items := make([]models.AccessReviewItem, 0, len(assignments))
+		for _, assignment := range assignments {
+			sw := owners[assignment.SoftwareID]
+			items = append(items, models.AccessReviewItem{
+				CampaignID:         campaign.ID,
+				AssignedSoftwareID: assignment.ID,
+				StaffID:            assignment.StaffID,
+				SoftwareID:         assignment.SoftwareID,
+				PlanID:             assignment.PlanID,
+				Source:             assignment.Source,
+				ReviewerID:         ChooseReviewer(campaign.Reviewer, assignment.StaffID, managers[assignment.StaffID], sw.OwnerID, sw.BackupOwnerID),
+			})
+		}
+		return tx.CreateInBatches(&items, 200).Error
+	})
+}
+
+// DecideReviewItem records a reviewer's keep or revoke decision. A revoke is carried out right away,
+// in the same transaction as the decision, and logged; when a match or rule granted the software,
+// the staff member is also excluded from it so that it is not assigned again. That exclusion has no
+// end date: it stands until removed and is listed in the campaign's report.
+func DecideReviewItem(item *models.AccessReviewItem, reviewerID uint, decision, note string) error {
+	var campaign models.AccessReviewCampaign
+	if err := config.DB.First(&campaign, item.CampaignID).Error; err != nil {
+		return err
+	}
+	if campaign.Status != CampaignOpen {
+		return ErrCampaignCompleted
+	}
+
+	now := time.Now()
+	var revoked *models.AssignedSoftware
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AccessReviewItem{}).
+			Where("id = ? AND (decision IS NULL OR decision = '')", item.ID).
+			Updates(map[string]interface{}{
+				"reviewer_id": reviewerID,
+				"decision":    decision,
+				"note":        note,
+				"decided_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrItemDecided
+		}
+
+		if decision != ReviewRevoke {
+			return nil
+		}
+		var err error
+		revoked, err = revokeReviewedAccess(tx, *item, campaign)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if revoked != nil {
+		logRevoked(*revoked, ActionReviewed)
+	}
+	return config.DB.First(item, item.ID).Error
+}
+
+// revokeReviewedAccess revokes the assignment behind a review item inside the caller's transaction
+// and returns it, or nil when the staff member no longer holds it
+func revokeReviewedAccess(tx *gorm.DB, item models.AccessReviewItem, campaign models.AccessReviewCampaign) (*models.AssignedSoftware, error) {
+	var assignment models.AssignedSoftware
+	if err := tx.First(&assignment, item.AssignedSoftwareID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	grants, err := loadGrants(tx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	if err := revokeAssignment(tx, assignment); err != nil {
+		return nil, err
+	}
+
+	exclusion, err := excludeAutoGranted(tx, assignment, grants, "Revoked in access review: "+campaign.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exclusion != nil {
+		if err := tx.Model(&models.AccessReviewItem{}).Where("id = ?", item.ID).
+			Update("exclusion_id", exclusion.ID).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &assignment, nil
+}
+
+// CompleteAccessReview closes a campaign. Items still pending stay undecided and show in its report.
+func CompleteAccessReview(campaign *models.AccessReviewCampaign) error {
+	now := time.Now()
+	result := config.DB.Model(&models.AccessReviewCampaign{}).
+		Where("id = ? AND status = ?", campaign.ID, CampaignOpen).
+		Updates(map[string]interface{}{"status": CampaignCompleted, "completed_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCampaignCompleted
+	}
+	return config.DB.First(campaign, campaign.ID).Error
+}
+
+// AccessReviewSummary counts the decisions of a campaign, overall and per reviewer
+func AccessReviewSummary(campaign models.AccessReviewCampaign) (models.AccessReviewReport, error) {
+	report := models.AccessReviewReport{
+		CampaignID:  campaign.ID,
+		Name:        campaign.Name,
+		Status:      campaign.Status,
+		Reviewers:   []models.ReviewerProgress{},
+		Exclusions:  []models.SoftwareExclusion{},
+		CompletedAt: campaign.CompletedAt,
+	}
+
+	var items []models.AccessReviewItem
+	if err := config.DB.Select("id", "reviewer_id", "decision").
+		Where("campaign_id = ?", campaign.ID).Find(&items).Error; err != nil {
+		return report, err
+	}
+
+	progress := make(map[uint]*models.ReviewerProgress)
+	var reviewerIDs []uint
+	for _, item := range items {
+		report.Total++
+		switch item.Decision {
+		case ReviewKeep:
+			report.Kept++
+		case ReviewRevoke:
+			report.Revoked++
+		default:
+			report.Pending++
+			if item.ReviewerID == nil {
+				report.Unassigned++
+			}
+		}
+		if item.ReviewerID == nil {
+			continue
+		}
+		p, ok := progress[*item.ReviewerID]
+		if !ok {
+			p = &models.ReviewerProgress{ReviewerID: *item.ReviewerID}
+			progress[*item.ReviewerID] = p
+			reviewerIDs = append(reviewerIDs, *item.ReviewerID)
+		}
+		p.Total++
+		if item.Decision != "" {
+			p.Decided++
+		}
+	}
+	if report.Total > 0 {
+		report.Completion = math.Round(float64(report.Kept+report.Revoked)/float64(report.Total)*10000) / 100
+	}
+
+	// Exclusions left by revocations that are still in force
+	if err := config.DB.Preload("Software").
+		Where("id IN (?)", config.DB.Model(&models.AccessReviewItem{}).Select("exclusion_id").
+			Where("campaign_id = ? AND exclusion_id IS NOT NULL", campaign.ID)).
+		Order("id").Find(&report.Exclusions).Error; err != nil {
+		return report, err
+	}
+
+	if len(reviewerIDs) > 0 {
+		var reviewers []models.StaffPlain
+		config.DB.Select("id", "email").Where("id IN ?", reviewerIDs).Find(&reviewers)
+		for _, reviewer := range reviewers {
+			progress[reviewer.ID].Email = reviewer.Email
+		}
+	}
+	for _, id := range reviewerIDs {
+		report.Reviewers = append(report.Reviewers, *progress[id])
+	}
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"log"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// futureStart returns the start date when it lies in the future, nil when the staff member has started
+func futureStart(startDate *time.Time, now time.Time) *time.Time {
+	if startDate == nil || !startDate.After(now) {
+		return nil
+	}
+	start := *startDate
+	return &start
+}
+
+// ActivateAssignment turns a pending assignment into an active one: it checks seats and budget as
+// CreateAssignment does, allocates a license key, logs the assignment and ticks it off the staff
+// member's onboarding.
+func ActivateAssignment(assignment models.AssignedSoftware) error {
+	var overBudget *models.Department
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimSeat(tx, assignment.SoftwareID); err != nil {
+			return err
+		}
+		var err error
+		if overBudget, err = budgetOverrun(tx, &assignment); err != nil {
+			return err
+		}
+		if err := tx.Model(&assignment).Updates(map[string]interface{}{
+			"status":      AssignmentActive,
+			"assigned_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return allocateLicenseKey(tx, &assignment)
+	})
+	if err != nil {
+		return err
+	}
+	if overBudget != nil {
+		alertOverBudget(*overBudget, assignment)
+	}
+	logAssignmentChange(assignment.StaffID, assignment.SoftwareID, ActionAssigned)
+	markOnboardingProvisioned(assignment.StaffID, assignment.SoftwareID)
+	return nil
+}
+
+// ProcessPendingActivations activates pending assignments whose start date has come. Assignments that
+// cannot be activated yet, e.g. for lack of seats, stay pending and are retried on the next run.
+func ProcessPendingActivations() error {
+	var assignments []models.AssignedSoftware
+	if err := config.DB.Where("status = ? AND starts_at <= ?", AssignmentPending, time.Now()).
+		Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		if err := ActivateAssignment(assignment); err != nil {
+			log.Printf("Activation of software %d for staff %d postponed: %v", assignment.SoftwareID, assignment.StaffID, err)
+			continue
+		}
+		log.Printf("▶️ Activated software %d for staff %d", assignment.SoftwareID, assignment.StaffID)
+	}
+	return nil
+}
+
+// RescheduleStaffStart moves the pending assignments of a staff member to a changed start date.
+// When the staff member has started already, they are activated right away.
+func RescheduleStaffStart(staffID uint, startDate *time.Time) error {
+	now := time.Now()
+	startsAt := futureStart(startDate, now)
+	if startsAt == nil {
+		startsAt = &now
+	}
+	if err := config.DB.Model(&models.AssignedSoftware{}).
+		Where("staff_id = ? AND status = ?", staffID, AssignmentPending).
+		Update("starts_at", startsAt).Error; err != nil {
+		return err
+	}
+	if startsAt.After(now) {
+		return nil
+	}
+
+	var assignments []models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ? AND status = ?", staffID, AssignmentPending).
+		Find(&assignments).Error; err != nil {
+		return err
+	}
+	for _, assignment := range assignments {
+		if err := ActivateAssignment(assignment); err != nil {
+			log.Printf("Activation of software %d for staff %d postponed: %v", assignment.SoftwareID, staffID, err)
+		}
+	}
+	return nil
+}
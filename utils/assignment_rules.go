@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// Scope types of a software assignment rule
+const (
+	ScopeDepartment = "Department"
+	ScopeTeam       = "Team"
+	ScopeStaff      = "Staff"
+)
+
+// NormalizeRuleScope maps a scope type in any letter case onto its canonical spelling
+func NormalizeRuleScope(scope string) (string, bool) {
+	for _, known := range []string{ScopeDepartment, ScopeTeam, ScopeStaff} {
+		if strings.EqualFold(scope, known) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// StaffCoveredByRule lists the staff a rule applies to
+func StaffCoveredByRule(rule models.SoftwareAssignment) ([]models.Staff, error) {
+	var staffList []models.Staff
+	query := config.DB
+	switch rule.ScopeType {
+	case ScopeDepartment:
+		query = query.Where("department_id = ?", rule.ScopeID)
+	case ScopeTeam:
+		query = query.Where("team_id = ?", rule.ScopeID)
+	case ScopeStaff:
+		query = query.Where("id = ?", rule.ScopeID)
+	default:
+		return staffList, nil
+	}
+	err := query.Find(&staffList).Error
+	return staffList, err
+}
+
+// ApplyAssignmentRule assigns the rule's software to every staff member it covers
+func ApplyAssignmentRule(rule models.SoftwareAssignment) error {
+	staffList, err := StaffCoveredByRule(rule)
+	if err != nil {
+		return err
+	}
+	AutoAssignSoftwareToStaffByUnit(rule.SoftwareID, rule.PlanID, staffList, SourceRule, rule.ID)
+	return nil
+}
+
+// SyncAssignmentRule is called after a rule changed from old to updated. Staff who were covered by
+// the old rule but not the updated one lose the access it granted; everyone now covered is assigned.
+func SyncAssignmentRule(old, updated models.SoftwareAssignment) error {
+	oldStaff, err := StaffCoveredByRule(old)
+	if err != nil {
+		return err
+	}
+	newStaff, err := StaffCoveredByRule(updated)
+	if err != nil {
+		return err
+	}
+	syncRuleCoverage(SourceRule, updated.ID, old.SoftwareID, oldStaff, updated.SoftwareID, updated.PlanID, newStaff)
+	return nil
+}
+
+// RevokeAssignmentRule removes the grant of a deleted rule from the staff it covered. Software is
+// revoked only from staff left without any other grant for it.
+func RevokeAssignmentRule(rule models.SoftwareAssignment) error {
+	staffList, err := StaffCoveredByRule(rule)
+	if err != nil {
+		return err
+	}
+	for _, staff := range staffList {
+		revokeGrant(staff.ID, rule.SoftwareID, SourceRule, rule.ID, ActionRuleDelete)
+	}
+	return nil
+}
+
+// StaffMatchingAttributeRule lists the staff whose attributes match an attribute rule's condition
+func StaffMatchingAttributeRule(rule models.SoftwareAttributeRule) ([]models.Staff, error) {
+	condition, err := ParseCondition(rule.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	var staffList []models.Staff
+	if err := config.DB.Preload("Department").Preload("Team").Find(&staffList).Error; err != nil {
+		return nil, err
+	}
+
+	matching := []models.Staff{}
+	for _, staff := range staffList {
+		if condition.Match(StaffAttributes(staff)) {
+			matching = append(matching, staff)
+		}
+	}
+	return matching, nil
+}
+
+// ApplyAttributeRule assigns the rule's software to every staff member matching its condition
+func ApplyAttributeRule(rule models.SoftwareAttributeRule) error {
+	staffList, err := StaffMatchingAttributeRule(rule)
+	if err != nil {
+		return err
+	}
+	AutoAssignSoftwareToStaffByUnit(rule.SoftwareID, rule.PlanID, staffList, SourceAttributeRule, rule.ID)
+	return nil
+}
+
+// SyncAttributeRule is called after an attribute rule changed from old to updated, with the staff
+// that matched the old rule. Staff who no longer match lose the access it granted.
+func SyncAttributeRule(oldSoftwareID uint, oldStaff []models.Staff, updated models.SoftwareAttributeRule) error {
+	newStaff, err := StaffMatchingAttributeRule(updated)
+	if err != nil {
+		return err
+	}
+	syncRuleCoverage(SourceAttributeRule, updated.ID, oldSoftwareID, oldStaff, updated.SoftwareID, updated.PlanID, newStaff)
+	return nil
+}
+
+// RevokeAttributeRule removes the grant of a deleted attribute rule from the staff it matched.
+// Software is revoked only from staff left without any other grant for it.
+func RevokeAttributeRule(rule models.SoftwareAttributeRule, matched []models.Staff) {
+	for _, staff := range matched {
+		revokeGrant(staff.ID, rule.SoftwareID, SourceAttributeRule, rule.ID, ActionRuleDelete)
+	}
+}
+
+// ReevaluateRulesForStaff re-applies assignment and attribute rules to a staff member whose
+// attributes changed: access no rule grants any more is revoked, and newly granted access is assigned.
+func ReevaluateRulesForStaff(staffID, departmentID, teamID uint) error {
+	revokeUngrantedAccess(staffID, departmentID, teamID)
+	return AutoAssignSoftwareToStaff(staffID, departmentID, teamID)
+}
+
+// syncRuleCoverage drops the grant of a rule from staff covered before a rule change but not after
+// it, then assigns the software to everyone covered now
+func syncRuleCoverage(sourceType string, ruleID, oldSoftwareID uint, oldStaff []models.Staff, softwareID uint, planID *uint, newStaff []models.Staff) {
+	stillCovered := make(map[uint]bool)
+	if oldSoftwareID == softwareID {
+		for _, staff := range newStaff {
+			stillCovered[staff.ID] = true
+		}
+	}
+	for _, staff := range oldStaff {
+		if !stillCovered[staff.ID] {
+			revokeGrant(staff.ID, oldSoftwareID, sourceType, ruleID, ActionUnassigned)
+		}
+	}
+
+	AutoAssignSoftwareToStaffByUnit(softwareID, planID, newStaff, sourceType, ruleID)
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Budget enforcement modes of a department
+const (
+	BudgetWarn   = "warn"
+	BudgetReject = "reject"
+)
+
+// ErrOverBudget is returned when an assignment would push a rejecting department past its annual budget
+var ErrOverBudget = errors.New("over budget: this assignment would push the department's committed software spend past its annual budget")
+
+// budgetOverrun checks whether adding record pushes the annualised committed spend of the staff member's
+// department past its budget. It returns the department when it does, or ErrOverBudget when that
+// department rejects overspend. Seats priced in another currency than the budget are not counted, and
+// neither are pending assignments, which commit no spend until they activate. It must run in the
+// assignment's transaction: it locks the department row, so that concurrent assignments to the same
+// department wait for each other instead of both fitting in the last of the budget.
+func budgetOverrun(tx *gorm.DB, record *models.AssignedSoftware) (*models.Department, error) {
+	var staff models.StaffPlain
+	if err := tx.Select("id", "department_id").First(&staff, record.StaffID).Error; err != nil || staff.DepartmentID == 0 {
+		return nil, nil
+	}
+	var department models.Department
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&department, staff.DepartmentID).Error; err != nil || department.AnnualBudget <= 0 {
+		return nil, nil
+	}
+
+	seatCost, currency, err := seatMonthlyCost(tx, record.SoftwareID, record.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if seatCost == 0 || currency != department.BudgetCurrency {
+		return nil, nil
+	}
+
+	committed, _, err := committedSpend(tx, department.ID, department.BudgetCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if (committed+seatCost)*12 <= department.AnnualBudget {
+		return nil, nil
+	}
+	if department.BudgetEnforcement == BudgetReject {
+		return nil, ErrOverBudget
+	}
+	return &department, nil
+}
+
+// alertOverBudget fires a reminder flagging an assignment that took its department over budget
+func alertOverBudget(department models.Department, record models.AssignedSoftware) {
+	committed, _, err := committedSpend(config.DB, department.ID, department.BudgetCurrency)
+	if err != nil {
+		committed = 0
+	}
+	var software models.Software
+	config.DB.Select("id", "name").First(&software, record.SoftwareID)
+	var staff models.StaffPlain
+	config.DB.Select("id", "email").First(&staff, record.StaffID)
+
+	departmentID := department.ID
+	fireReminder(models.Reminder{
+		Kind:         ReminderBudgetOverspend,
+		DedupKey:     fmt.Sprintf("%s:%d:%d", ReminderBudgetOverspend, department.ID, record.ID),
+		DepartmentID: &departmentID,
+		DueDate:      time.Now(),
+		Message: fmt.Sprintf("Assigning %s to %s takes %s committed software spend to %.2f %s a year against a budget of %.2f %s",
+			software.Name, staff.Email, department.Name, roundMoney(committed*12), department.BudgetCurrency,
+			department.AnnualBudget, department.BudgetCurrency),
+	})
+}
+
+// seatMonthlyCost is the monthly cost and currency of one seat of a software at an optional plan
+func seatMonthlyCost(db *gorm.DB, softwareID uint, planID *uint) (float64, string, error) {
+	var software models.Software
+	if err := db.First(&software, softwareID).Error; err != nil {
+		return 0, "", err
+	}
+	var plan *models.SoftwarePlan
+	if planID != nil {
+		var p models.SoftwarePlan
+		if err := db.First(&p, *planID).Error; err == nil {
+			plan = &p
+		}
+	}
+	price, billingPeriod, currency := priceFor(software, plan)
+	return MonthlySeatCost(price, billingPeriod), currency, nil
+}
+
+// committedSpend sums the monthly cost of the seats a department's staff hold right now. Seats priced
+// in currency are summed into the first value; other currencies are returned per currency.
+func committedSpend(db *gorm.DB, departmentID uint, currency string) (float64, map[string]float64, error) {
+	var rows []struct {
+		SeatPrice     float64
+		BillingPeriod string
+		Currency      string
+		Seats         int
+	}
+	err := db.Table("assigned_software").
+		Select(`
+			COALESCE(software_plans.seat_price, software.seat_price) AS seat_price,
+			COALESCE(software_plans.billing_period, software.billing_period) AS billing_period,
+			COALESCE(software_plans.currency, software.currency) AS currency,
+			COUNT(*) AS seats
+		`).
+		Joins("JOIN staff ON staff.id = assigned_software.staff_id").
+		Joins("JOIN software ON software.id = assigned_software.software_id").
+		Joins("LEFT JOIN software_plans ON software_plans.id = assigned_software.plan_id").
+		Where("staff.department_id = ? AND assigned_software.status <> ?", departmentID, AssignmentPending).
+		Group(`
+			software.seat_price, software.billing_period, software.currency,
+			software_plans.seat_price, software_plans.billing_period, software_plans.currency
+		`).
+		Scan(&rows).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total := 0.0
+	other := make(map[string]float64)
+	for _, row := range rows {
+		monthly := MonthlySeatCost(row.SeatPrice, row.BillingPeriod) * float64(row.Seats)
+		if row.Currency == currency {
+			total += monthly
+		} else {
+			other[row.Currency] = roundMoney(other[row.Currency] + monthly)
+		}
+	}
+	return total, other, nil
+}
+
+// GetDepartmentBudget compares a department's budget with its spend for the year of now. Spend to date
+// comes from the chargeback of each completed month; the forecast adds the committed monthly cost for
+// the current and remaining months.
+func GetDepartmentBudget(departmentID uint, now time.Time) (models.BudgetStatus, error) {
+	var department models.Department
+	if err := config.DB.First(&department, departmentID).Error; err != nil {
+		return models.BudgetStatus{}, err
+	}
+
+	committed, other, err := committedSpend(config.DB, department.ID, department.BudgetCurrency)
+	if err != nil {
+		return models.BudgetStatus{}, err
+	}
+
+	spent := 0.0
+	for month := time.January; month < now.Month(); month++ {
+		lines, err := BuildChargeback(fmt.Sprintf("%d-%02d", now.Year(), month))
+		if err != nil {
+			return models.BudgetStatus{}, err
+		}
+		for _, line := range lines {
+			if line.DepartmentID == department.ID && line.Currency == department.BudgetCurrency {
+				spent += line.Cost
+			}
+		}
+	}
+
+	remainingMonths := float64(13 - now.Month())
+	forecast := spent + committed*remainingMonths
+
+	status := models.BudgetStatus{
+		DepartmentID:     department.ID,
+		Department:       department.Name,
+		Year:             now.Year(),
+		Currency:         department.BudgetCurrency,
+		AnnualBudget:     department.AnnualBudget,
+		Enforcement:      department.BudgetEnforcement,
+		CommittedMonthly: roundMoney(committed),
+		CommittedAnnual:  roundMoney(committed * 12),
+		SpentToDate:      roundMoney(spent),
+		Forecast:         roundMoney(forecast),
+		OtherCurrencies:  other,
+	}
+	if department.AnnualBudget > 0 {
+		status.Remaining = roundMoney(department.AnnualBudget - forecast)
+		status.Utilization = math.Round(forecast/department.AnnualBudget*1000) / 1000
+		status.OverBudget = forecast > department.AnnualBudget
+	}
+	return status, nil
+}
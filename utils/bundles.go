@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// ScopeOrganization is the scope of a bundle match covering every staff member
+const ScopeOrganization = "Organization"
+
+// NormalizeBundleScope maps a bundle match scope in any letter case onto its canonical spelling
+func NormalizeBundleScope(scope string) (string, bool) {
+	for _, known := range []string{ScopeOrganization, ScopeDepartment, ScopeTeam} {
+		if strings.EqualFold(scope, known) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// bundleMatchCovers reports whether a bundle match applies to staff of the given department and team
+func bundleMatchCovers(match models.SoftwareBundleMatch, departmentID, teamID uint) bool {
+	switch match.ScopeType {
+	case ScopeOrganization:
+		return true
+	case ScopeDepartment:
+		return match.ScopeID == departmentID
+	case ScopeTeam:
+		return match.ScopeID == teamID
+	}
+	return false
+}
+
+// StaffCoveredByBundleMatch lists the staff a bundle match applies to
+func StaffCoveredByBundleMatch(match models.SoftwareBundleMatch) ([]models.Staff, error) {
+	var staffList []models.Staff
+	query := config.DB
+	switch match.ScopeType {
+	case ScopeOrganization:
+	case ScopeDepartment:
+		query = query.Where("department_id = ?", match.ScopeID)
+	case ScopeTeam:
+		query = query.Where("team_id = ?", match.ScopeID)
+	default:
+		return staffList, nil
+	}
+	err := query.Find(&staffList).Error
+	return staffList, err
+}
+
+// bundleItems loads the software of a bundle, prerequisites first
+func bundleItems(bundleID uint) ([]models.SoftwareBundleItem, error) {
+	var items []models.SoftwareBundleItem
+	if err := config.DB.Where("bundle_id = ?", bundleID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.SoftwareID
+	}
+	rank := prerequisiteRank(ids)
+	sort.SliceStable(items, func(i, j int) bool {
+		return rank[items[i].SoftwareID] < rank[items[j].SoftwareID]
+	})
+	return items, nil
+}
+
+// ApplyBundleMatch assigns every software of the bundle to the staff a bundle match covers
+func ApplyBundleMatch(match models.SoftwareBundleMatch, staffList []models.Staff) error {
+	items, err := bundleItems(match.BundleID)
+	if err != nil {
+		return err
+	}
+	bundleID := match.BundleID
+	for _, item := range items {
+		grantToStaff(item.SoftwareID, staffList, models.AssignmentGrant{
+			SourceType: SourceBundleMatch,
+			SourceID:   match.ID,
+			PlanID:     item.PlanID,
+			BundleID:   &bundleID,
+		})
+	}
+	return nil
+}
+
+// RevokeBundleMatch removes the grants of a deleted bundle match from the staff it covered. Each
+// software is revoked only from staff left without any other grant for it.
+func RevokeBundleMatch(match models.SoftwareBundleMatch, staffList []models.Staff) error {
+	items, err := bundleItems(match.BundleID)
+	if err != nil {
+		return err
+	}
+	for _, staff := range staffList {
+		for _, item := range items {
+			revokeGrant(staff.ID, item.SoftwareID, SourceBundleMatch, match.ID, ActionUnassigned)
+		}
+	}
+	return nil
+}
+
+// AssignBundle gives a bundle directly to a staff member and assigns each of its software
+func AssignBundle(bundle models.SoftwareBundle, staff models.Staff) (models.BundleAssignment, error) {
+	assignment := models.BundleAssignment{BundleID: bundle.ID, StaffID: staff.ID}
+	if err := config.DB.Create(&assignment).Error; err != nil {
+		return assignment, err
+	}
+
+	items, err := bundleItems(bundle.ID)
+	if err != nil {
+		return assignment, err
+	}
+	bundleID := bundle.ID
+	for _, item := range items {
+		grantToStaff(item.SoftwareID, []models.Staff{staff}, models.AssignmentGrant{
+			SourceType: SourceBundle,
+			SourceID:   assignment.ID,
+			PlanID:     item.PlanID,
+			BundleID:   &bundleID,
+		})
+	}
+	return assignment, nil
+}
+
+// UnassignBundle takes a bundle away from a staff member. Each of its software is revoked unless
+// something else grants it too.
+func UnassignBundle(assignment models.BundleAssignment) error {
+	items, err := bundleItems(assignment.BundleID)
+	if err != nil {
+		return err
+	}
+	if err := config.DB.Delete(&assignment).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		revokeGrant(assignment.StaffID, item.SoftwareID, SourceBundle, assignment.ID, ActionUnassigned)
+	}
+	return nil
+}
+
+// StaffHoldingBundle lists the staff given a bundle directly or through a bundle match
+func StaffHoldingBundle(bundleID uint) ([]models.Staff, error) {
+	var matches []models.SoftwareBundleMatch
+	if err := config.DB.Where("bundle_id = ?", bundleID).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	holders := make(map[uint]models.Staff)
+	for _, match := range matches {
+		staffList, err := StaffCoveredByBundleMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		for _, staff := range staffList {
+			holders[staff.ID] = staff
+		}
+	}
+
+	var direct []models.Staff
+	if err := config.DB.
+		Where("id IN (SELECT staff_id FROM bundle_assignments WHERE bundle_id = ?)", bundleID).
+		Find(&direct).Error; err != nil {
+		return nil, err
+	}
+	for _, staff := range direct {
+		holders[staff.ID] = staff
+	}
+
+	staffList := make([]models.Staff, 0, len(holders))
+	for _, staff := range holders {
+		staffList = append(staffList, staff)
+	}
+	return staffList, nil
+}
+
+// SyncBundleHolders brings the assignments of staff holding a bundle in line with its items after
+// they changed: software dropped from the bundle loses its bundle grant, added software is assigned.
+func SyncBundleHolders(staffList []models.Staff) {
+	for _, staff := range staffList {
+		if strings.EqualFold(staff.Status, "inactive") {
+			continue
+		}
+		revokeUngrantedAccess(staff.ID, staff.DepartmentID, staff.TeamID)
+		if err := AutoAssignSoftwareToStaff(staff.ID, staff.DepartmentID, staff.TeamID); err != nil {
+			log.Printf("Failed to re-assign software for staff %d: %v", staff.ID, err)
+		}
+	}
+}
+
+// PreviewBundleAssign works out what giving a bundle to a list of staff would do, item by item
+func PreviewBundleAssign(bundle models.SoftwareBundle, staffList []models.Staff, source string) (models.AssignmentPreview, error) {
+	return previewBundle(bundle, source, func(item models.SoftwareBundleItem) (models.AssignmentPreview, error) {
+		return PreviewAutoAssign(item.SoftwareID, item.PlanID, staffList, source)
+	})
+}
+
+// PreviewBundleRevoke works out what removing a bundle grant from a list of staff would do, item by item
+func PreviewBundleRevoke(bundle models.SoftwareBundle, staffList []models.Staff, sourceType string, sourceID uint) (models.AssignmentPreview, error) {
+	return previewBundle(bundle, sourceType, func(item models.SoftwareBundleItem) (models.AssignmentPreview, error) {
+		return PreviewAutoRevoke(item.SoftwareID, staffList, sourceType, sourceID)
+	})
+}
+
+// previewBundle merges the previews of every item of a bundle into one
+func previewBundle(bundle models.SoftwareBundle, source string, previewItem func(models.SoftwareBundleItem) (models.AssignmentPreview, error)) (models.AssignmentPreview, error) {
+	bundleID := bundle.ID
+	preview := models.AssignmentPreview{
+		DryRun:   true,
+		BundleID: &bundleID,
+		Software: bundle.Name,
+		Source:   source,
+		Changes:  []models.AssignmentChange{},
+	}
+
+	items, err := bundleItems(bundle.ID)
+	if err != nil {
+		return preview, err
+	}
+	for _, item := range items {
+		itemPreview, err := previewItem(item)
+		if err != nil {
+			return preview, err
+		}
+		preview.Assign += itemPreview.Assign
+		preview.Upgrade += itemPreview.Upgrade
+		preview.Revoke += itemPreview.Revoke
+		preview.Skip += itemPreview.Skip
+		preview.Unchanged += itemPreview.Unchanged
+		preview.Changes = append(preview.Changes, itemPreview.Changes...)
+	}
+	return preview, nil
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// StaffSoftware identifies one staff member's hold on one software
+type StaffSoftware struct {
+	StaffID    uint
+	SoftwareID uint
+}
+
+// ParseBillingPeriod turns "YYYY-MM" into the half-open month [start, end)
+func ParseBillingPeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01", period, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q, expected YYYY-MM", period)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// SeatDaysInPeriod works out how many days each staff member held each software within [start, end).
+// Holding intervals are rebuilt from the assignment logs (ordered by changed_at) so mid-period joins
+// and leaves are prorated. Current assignments with no open interval in the logs are counted from
+// their assigned_at, which covers rows created without a log entry.
+func SeatDaysInPeriod(start, end time.Time, logs []models.SoftwareAssignmentLog, current []models.AssignedSoftware) map[StaffSoftware]float64 {
+	type interval struct {
+		from time.Time
+		to   *time.Time
+	}
+	intervals := make(map[StaffSoftware][]interval)
+
+	for _, entry := range logs {
+		key := StaffSoftware{entry.StaffID, entry.SoftwareID}
+		list := intervals[key]
+		open := len(list) > 0 && list[len(list)-1].to == nil
+
+		if isGrantAction(entry.Action) {
+			if !open {
+				intervals[key] = append(list, interval{from: entry.ChangedAt})
+			}
+		} else if open {
+			changedAt := entry.ChangedAt
+			list[len(list)-1].to = &changedAt
+		}
+	}
+
+	for _, assignment := range current {
+		key := StaffSoftware{assignment.StaffID, assignment.SoftwareID}
+		list := intervals[key]
+		if len(list) > 0 && list[len(list)-1].to == nil {
+			continue
+		}
+		from := assignment.AssignedAt
+		if len(list) > 0 && from.Before(*list[len(list)-1].to) {
+			from = *list[len(list)-1].to
+		}
+		intervals[key] = append(list, interval{from: from})
+	}
+
+	days := make(map[StaffSoftware]float64)
+	for key, list := range intervals {
+		total := 0.0
+		for _, iv := range list {
+			from, to := iv.from, end
+			if iv.to != nil && iv.to.Before(to) {
+				to = *iv.to
+			}
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(from) {
+				total += to.Sub(from).Hours() / 24
+			}
+		}
+		if total > 0 {
+			days[key] = total
+		}
+	}
+	return days
+}
+
+// BuildChargeback splits each software's cost for a "YYYY-MM" billing period across departments,
+// in proportion to the seat-days each department held. Staff are attributed to their current department.
+// Seats priced in different currencies, through their plans, are charged on separate lines.
+func BuildChargeback(period string) ([]models.ChargebackLine, error) {
+	start, end, err := ParseBillingPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []models.SoftwareAssignmentLog
+	if err := config.DB.Where("changed_at < ?", end).Order("changed_at ASC, id ASC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	// Pending assignments hold no seat until they activate, which logs them as assigned
+	var current []models.AssignedSoftware
+	if err := config.DB.Where("assigned_at < ? AND status <> ?", end, AssignmentPending).Find(&current).Error; err != nil {
+		return nil, err
+	}
+
+	var staff []models.StaffPlain
+	if err := config.DB.Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	var departments []models.Department
+	if err := config.DB.Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	var software []models.Software
+	if err := config.DB.Find(&software).Error; err != nil {
+		return nil, err
+	}
+	var plans []models.SoftwarePlan
+	if err := config.DB.Find(&plans).Error; err != nil {
+		return nil, err
+	}
+
+	staffDept := make(map[uint]uint)
+	for _, s := range staff {
+		staffDept[s.ID] = s.DepartmentID
+	}
+	deptNames := make(map[uint]string)
+	for _, d := range departments {
+		deptNames[d.ID] = d.Name
+	}
+	softwareByID := make(map[uint]models.Software)
+	for _, s := range software {
+		softwareByID[s.ID] = s
+	}
+	plansByID := make(map[uint]models.SoftwarePlan)
+	for _, p := range plans {
+		plansByID[p.ID] = p
+	}
+	// Logs carry no plan, so each holder is priced at the plan of their current assignment
+	holderPlan := make(map[StaffSoftware]*models.SoftwarePlan)
+	for _, a := range current {
+		if a.PlanID == nil {
+			continue
+		}
+		if plan, ok := plansByID[*a.PlanID]; ok {
+			holderPlan[StaffSoftware{a.StaffID, a.SoftwareID}] = &plan
+		}
+	}
+
+	type key struct {
+		departmentID uint
+		softwareID   uint
+		currency     string
+	}
+	periodDays := end.Sub(start).Hours() / 24
+	seatDays := make(map[key]float64)
+	costs := make(map[key]float64)
+	softwareTotals := make(map[uint]float64)
+	for holder, days := range SeatDaysInPeriod(start, end, logs, current) {
+		sw, ok := softwareByID[holder.SoftwareID]
+		if !ok {
+			continue
+		}
+		price, billingPeriod, currency := priceFor(sw, holderPlan[holder])
+
+		k := key{staffDept[holder.StaffID], sw.ID, currency}
+		seatDays[k] += days
+		costs[k] += MonthlySeatCost(price, billingPeriod) * days / periodDays
+		softwareTotals[sw.ID] += days
+	}
+
+	lines := make([]models.ChargebackLine, 0, len(seatDays))
+	for k, days := range seatDays {
+		sw := softwareByID[k.softwareID]
+		department := deptNames[k.departmentID]
+		if department == "" {
+			department = "Unassigned"
+		}
+		lines = append(lines, models.ChargebackLine{
+			Period:       period,
+			DepartmentID: k.departmentID,
+			Department:   department,
+			SoftwareID:   sw.ID,
+			Software:     sw.Name,
+			Currency:     k.currency,
+			SeatDays:     math.Round(days*100) / 100,
+			AverageSeats: math.Round(days/periodDays*100) / 100,
+			Share:        math.Round(days/softwareTotals[sw.ID]*10000) / 10000,
+			Cost:         roundMoney(costs[k]),
+		})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Software != lines[j].Software {
+			return lines[i].Software < lines[j].Software
+		}
+		if lines[i].Department != lines[j].Department {
+			return lines[i].Department < lines[j].Department
+		}
+		return lines[i].Currency < lines[j].Currency
+	})
+	return lines, nil
+}
+
+// isGrantAction reports whether a log action gives the staff member the software
+func isGrantAction(action string) bool {
+	return strings.HasPrefix(action, ActionAssigned)
+}
@@ -0,0 +1,314 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"software_management/models"
+)
+
+// Condition is a parsed attribute-rule condition, evaluated against a staff member's attributes.
+//
+// Conditions compare staff fields with values and combine comparisons with AND, OR, NOT and
+// parentheses, for example:
+//
+//	status == "Active" AND department == "Engineering" AND email ends_with "@shuttlers.co" AND employment_type != "contractor"
+//
+// Operators are ==, !=, contains, starts_with, ends_with and in ["a", "b"]. All comparisons ignore case.
+type Condition interface {
+	Match(attributes map[string]string) bool
+}
+
+// ConditionFields are the staff fields a condition can refer to
+var ConditionFields = []string{
+	"first_name", "last_name", "email", "status", "employment_type",
+	"department", "department_id", "team", "team_id",
+}
+
+// StaffAttributes flattens a staff member into the fields conditions refer to.
+// Department and Team must be preloaded for their names to be available.
+func StaffAttributes(staff models.Staff) map[string]string {
+	return map[string]string{
+		"first_name":      staff.FirstName,
+		"last_name":       staff.LastName,
+		"email":           staff.Email,
+		"status":          staff.Status,
+		"employment_type": staff.EmploymentType,
+		"department":      staff.Department.Name,
+		"department_id":   strconv.FormatUint(uint64(staff.DepartmentID), 10),
+		"team":            staff.Team.Name,
+		"team_id":         strconv.FormatUint(uint64(staff.TeamID), 10),
+	}
+}
+
+type andCondition struct{ left, right Condition }
+
+func (c andCondition) Match(attributes map[string]string) bool {
+	return c.left.Match(attributes) && c.right.Match(attributes)
+}
+
+type orCondition struct{ left, right Condition }
+
+func (c orCondition) Match(attributes map[string]string) bool {
+	return c.left.Match(attributes) || c.right.Match(attributes)
+}
+
+type notCondition struct{ inner Condition }
+
+func (c notCondition) Match(attributes map[string]string) bool {
+	return !c.inner.Match(attributes)
+}
+
+type comparison struct {
+	field  string
+	op     string
+	values []string // lower-cased
+}
+
+func (c comparison) Match(attributes map[string]string) bool {
+	actual := strings.ToLower(strings.TrimSpace(attributes[c.field]))
+	switch c.op {
+	case "==":
+		return actual == c.values[0]
+	case "!=":
+		return actual != c.values[0]
+	case "contains":
+		return strings.Contains(actual, c.values[0])
+	case "starts_with":
+		return strings.HasPrefix(actual, c.values[0])
+	case "ends_with":
+		return strings.HasSuffix(actual, c.values[0])
+	case "in":
+		for _, value := range c.values {
+			if actual == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseCondition parses a condition expression, rejecting unknown fields and operators
+func ParseCondition(expression string) (Condition, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{tokens: tokens}
+	if p.peek().kind == tokenEnd {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return condition, nil
+}
+
+const (
+	tokenEnd = iota
+	tokenWord
+	tokenString
+	tokenSymbol
+)
+
+type conditionToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+func tokenizeCondition(expression string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{tokenString, string(runes[i+1 : end]), i})
+			i = end + 1
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, conditionToken{tokenSymbol, string(r), i})
+			i++
+		case strings.ContainsRune("=!&|", r):
+			end := i + 1
+			if end < len(runes) && strings.ContainsRune("=&|", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{tokenSymbol, string(runes[i:end]), i})
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()[],=!&|\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{tokenWord, string(runes[i:end]), i})
+			i = end
+		}
+	}
+	return append(tokens, conditionToken{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) peek() conditionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() conditionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether tok is a bare word or symbol spelling one of the given keywords
+func isKeyword(tok conditionToken, keywords ...string) bool {
+	if tok.kind != tokenWord && tok.kind != tokenSymbol {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(tok.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (Condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or", "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (Condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "and", "&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (Condition, error) {
+	if isKeyword(p.peek(), "not", "!") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{inner}, nil
+	}
+	if tok := p.peek(); tok.kind == tokenSymbol && tok.text == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenSymbol || closing.text != ")" {
+			return nil, fmt.Errorf("expected ) at position %d", closing.pos)
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (Condition, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenWord {
+		return nil, fmt.Errorf("expected a field name at position %d", fieldTok.pos)
+	}
+	field := strings.ToLower(fieldTok.text)
+	known := false
+	for _, f := range ConditionFields {
+		if f == field {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown field %q, expected one of %s", fieldTok.text, strings.Join(ConditionFields, ", "))
+	}
+
+	opTok := p.next()
+	op := strings.ToLower(opTok.text)
+	switch op {
+	case "=", "==":
+		op = "=="
+	case "!=", "contains", "starts_with", "ends_with", "in":
+	default:
+		return nil, fmt.Errorf("unknown operator %q at position %d", opTok.text, opTok.pos)
+	}
+
+	if op != "in" {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return comparison{field: field, op: op, values: []string{value}}, nil
+	}
+
+	if open := p.next(); open.kind != tokenSymbol || open.text != "[" {
+		return nil, fmt.Errorf("expected [ after in at position %d", open.pos)
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		sep := p.next()
+		if sep.kind == tokenSymbol && sep.text == "]" {
+			break
+		}
+		if sep.kind != tokenSymbol || sep.text != "," {
+			return nil, fmt.Errorf("expected , or ] at position %d", sep.pos)
+		}
+	}
+	return comparison{field: field, op: op, values: values}, nil
+}
+
+func (p *conditionParser) parseValue() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenString && tok.kind != tokenWord {
+		return "", fmt.Errorf("expected a value at position %d", tok.pos)
+	}
+	return strings.ToLower(strings.TrimSpace(tok.text)), nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"sort"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// FindUpcomingRenewals returns contracts whose notice deadline or end date falls within the window.
+// Contracts that have already ended are left out.
+func FindUpcomingRenewals(within time.Duration) ([]models.ContractRenewal, error) {
+	now := time.Now()
+	cutoff := now.Add(within)
+
+	var contracts []models.Contract
+	if err := config.DB.Preload("Vendor").Preload("Software").
+		Where("end_date >= ?", now).
+		Find(&contracts).Error; err != nil {
+		return nil, err
+	}
+
+	renewals := []models.ContractRenewal{}
+	for _, contract := range contracts {
+		deadline := contract.NoticeDeadline()
+		if contract.EndDate.After(cutoff) && deadline.After(cutoff) {
+			continue
+		}
+		renewals = append(renewals, models.ContractRenewal{
+			Contract:        contract,
+			NoticeDeadline:  deadline,
+			DaysUntilNotice: daysUntil(now, deadline),
+			DaysUntilEnd:    daysUntil(now, contract.EndDate),
+		})
+	}
+
+	sort.Slice(renewals, func(i, j int) bool {
+		return renewals[i].NoticeDeadline.Before(renewals[j].NoticeDeadline)
+	})
+	return renewals, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"software_management/models"
+)
+
+// Billing periods a software price can be quoted in
+const (
+	BillingMonthly   = "monthly"
+	BillingQuarterly = "quarterly"
+	BillingAnnual    = "annual"
+)
+
+// MonthlySeatCost normalises a per-seat price to a monthly amount
+func MonthlySeatCost(price float64, billingPeriod string) float64 {
+	switch strings.ToLower(billingPeriod) {
+	case BillingAnnual, "yearly":
+		return price / 12
+	case BillingQuarterly:
+		return price / 3
+	default:
+		return price
+	}
+}
+
+// BuildSpendReport rolls spend rows up into per-department, per-team and per-software totals.
+// Lines are kept per currency so that prices in different currencies are never summed together.
+func BuildSpendReport(rows []models.SpendRow) models.SpendReport {
+	type key struct {
+		id       uint
+		currency string
+	}
+
+	departments := make(map[key]*models.SpendLine)
+	teams := make(map[key]*models.SpendLine)
+	software := make(map[key]*models.SpendLine)
+
+	add := func(lines map[key]*models.SpendLine, id uint, name, currency string, seats int, monthly float64) {
+		k := key{id, currency}
+		line, ok := lines[k]
+		if !ok {
+			line = &models.SpendLine{ID: id, Name: name, Currency: currency}
+			lines[k] = line
+		}
+		line.Seats += seats
+		line.MonthlyCost += monthly
+	}
+
+	for _, row := range rows {
+		monthly := MonthlySeatCost(row.SeatPrice, row.BillingPeriod) * float64(row.Seats)
+		add(departments, row.DepartmentID, row.Department, row.Currency, row.Seats, monthly)
+		add(teams, row.TeamID, row.Team, row.Currency, row.Seats, monthly)
+		add(software, row.SoftwareID, row.Software, row.Currency, row.Seats, monthly)
+	}
+
+	return models.SpendReport{
+		ByDepartment: spendLines(departments),
+		ByTeam:       spendLines(teams),
+		BySoftware:   spendLines(software),
+	}
+}
+
+// spendLines flattens a line map, fills in annual cost and sorts by cost descending
+func spendLines[K comparable](lines map[K]*models.SpendLine) []models.SpendLine {
+	result := make([]models.SpendLine, 0, len(lines))
+	for _, line := range lines {
+		line.AnnualCost = roundMoney(line.MonthlyCost * 12)
+		line.MonthlyCost = roundMoney(line.MonthlyCost)
+		result = append(result, *line)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MonthlyCost != result[j].MonthlyCost {
+			return result[i].MonthlyCost > result[j].MonthlyCost
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// roundMoney rounds an amount to two decimal places
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// oneDay is a calendar day as a duration
+const oneDay = 24 * time.Hour
+
+// ParseWithin parses a look-ahead window such as "60d", "8w" or "72h".
+// A bare number is read as a number of days.
+func ParseWithin(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	case strings.HasSuffix(value, "h"), strings.HasSuffix(value, "m"), strings.HasSuffix(value, "s"):
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return d, nil
+	}
+
+	number := value
+	if unit == 0 {
+		unit = 24 * time.Hour
+	} else {
+		number = value[:len(value)-1]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// daysUntil returns the number of whole days from now until t (negative if t has passed)
+func daysUntil(now, t time.Time) int {
+	return int(t.Sub(now).Hours() / 24)
+}
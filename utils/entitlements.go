@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"log"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// entitlement is a software a staff member should hold, with the best plan, the first source granting
+// it and every match or rule that grants it
+type entitlement struct {
+	SoftwareID uint
+	PlanID     *uint
+	Source     string
+	Grants     []models.AssignmentGrant
+}
+
+// grantSources holds every match and rule that can grant software, loaded once so that
+// entitlements of many staff can be resolved without querying them again
+type grantSources struct {
+	tiers          map[uint]int
+	deptMatches    []models.SoftwareDepartmentMatch
+	teamMatches    []models.SoftwareTeamMatch
+	orgMatches     []models.SoftwareOrganizationMatch
+	rules          []models.SoftwareAssignment
+	attributeRules []models.SoftwareAttributeRule
+	bundleItems    map[uint][]models.SoftwareBundleItem
+	bundleMatches  []models.SoftwareBundleMatch
+	bundles        []models.BundleAssignment
+	conditions     map[uint]Condition
+	excluded       map[uint]map[uint]bool
+}
+
+func loadGrantSources() (*grantSources, error) {
+	tiers, err := planTiers()
+	if err != nil {
+		return nil, err
+	}
+	g := &grantSources{tiers: tiers, conditions: make(map[uint]Condition)}
+
+	if err := config.DB.Find(&g.deptMatches).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Find(&g.teamMatches).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Find(&g.orgMatches).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Find(&g.rules).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Find(&g.attributeRules).Error; err != nil {
+		return nil, err
+	}
+	if g.excluded, err = activeExclusions(0); err != nil {
+		return nil, err
+	}
+	var items []models.SoftwareBundleItem
+	if err := config.DB.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	g.bundleItems = make(map[uint][]models.SoftwareBundleItem)
+	for _, item := range items {
+		g.bundleItems[item.BundleID] = append(g.bundleItems[item.BundleID], item)
+	}
+	if err := config.DB.Find(&g.bundleMatches).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Find(&g.bundles).Error; err != nil {
+		return nil, err
+	}
+	for _, rule := range g.attributeRules {
+		condition, err := ParseCondition(rule.Condition)
+		if err != nil {
+			log.Printf("Skipping attribute rule %d: %v", rule.ID, err)
+			continue
+		}
+		g.conditions[rule.ID] = condition
+	}
+	return g, nil
+}
+
+// resolve collects the software granted to a staff member by department, team and org matches, by
+// scoped and attribute assignment rules and by bundles, given directly or through bundle matches. When several grant the same software, the first source wins,
+// the highest plan is kept and each is recorded as a grant. Software the staff member is excluded from
+// is left out. attributes may be nil when no attribute rule should be evaluated.
+func (g *grantSources) resolve(staffID, departmentID, teamID uint, attributes map[string]string) []entitlement {
+	var entitlements []entitlement
+	index := make(map[uint]int)
+	add := func(softwareID uint, planID *uint, sourceType string, sourceID uint, bundleID *uint) {
+		if g.excluded[staffID][softwareID] {
+			return
+		}
+		grant := models.AssignmentGrant{SourceType: sourceType, SourceID: sourceID, PlanID: planID, BundleID: bundleID}
+		if i, ok := index[softwareID]; ok {
+			entitlements[i].PlanID = higherPlan(entitlements[i].PlanID, planID, g.tiers)
+			entitlements[i].Grants = append(entitlements[i].Grants, grant)
+			return
+		}
+		index[softwareID] = len(entitlements)
+		entitlements = append(entitlements, entitlement{
+			SoftwareID: softwareID,
+			PlanID:     planID,
+			Source:     assignmentSource(grant),
+			Grants:     []models.AssignmentGrant{grant},
+		})
+	}
+
+	for _, match := range g.deptMatches {
+		if match.DepartmentID == departmentID {
+			add(match.SoftwareID, match.PlanID, SourceDepartment, match.ID, nil)
+		}
+	}
+	for _, match := range g.teamMatches {
+		if match.TeamID == teamID {
+			add(match.SoftwareID, match.PlanID, SourceTeam, match.ID, nil)
+		}
+	}
+	for _, match := range g.orgMatches {
+		add(match.SoftwareID, match.PlanID, SourceOrganization, match.ID, nil)
+	}
+
+	// Assignment rules scoped to the staff member, their department or their team
+	for _, rule := range g.rules {
+		if (rule.ScopeType == ScopeStaff && rule.ScopeID == staffID) ||
+			(rule.ScopeType == ScopeDepartment && rule.ScopeID == departmentID) ||
+			(rule.ScopeType == ScopeTeam && rule.ScopeID == teamID) {
+			add(rule.SoftwareID, rule.PlanID, SourceRule, rule.ID, nil)
+		}
+	}
+
+	// Attribute rules whose condition matches the staff member
+	if attributes != nil {
+		for _, rule := range g.attributeRules {
+			if condition, ok := g.conditions[rule.ID]; ok && condition.Match(attributes) {
+				add(rule.SoftwareID, rule.PlanID, SourceAttributeRule, rule.ID, nil)
+			}
+		}
+	}
+
+	// Bundles matched to the staff member's organization, department or team, then bundles given directly
+	for _, match := range g.bundleMatches {
+		if bundleMatchCovers(match, departmentID, teamID) {
+			bundleID := match.BundleID
+			for _, item := range g.bundleItems[bundleID] {
+				add(item.SoftwareID, item.PlanID, SourceBundleMatch, match.ID, &bundleID)
+			}
+		}
+	}
+	for _, assignment := range g.bundles {
+		if assignment.StaffID == staffID {
+			bundleID := assignment.BundleID
+			for _, item := range g.bundleItems[bundleID] {
+				add(item.SoftwareID, item.PlanID, SourceBundle, assignment.ID, &bundleID)
+			}
+		}
+	}
+
+	return entitlements
+}
+
+// resolveEntitlements works out the software a single staff member is entitled to, see grantSources.resolve
+func resolveEntitlements(staffID, departmentID, teamID uint) ([]entitlement, map[uint]int, error) {
+	g, err := loadGrantSources()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attributes map[string]string
+	if len(g.attributeRules) > 0 {
+		var staff models.Staff
+		if err := config.DB.Preload("Department").Preload("Team").First(&staff, staffID).Error; err == nil {
+			attributes = StaffAttributes(staff)
+		}
+	}
+	return g.resolve(staffID, departmentID, teamID, attributes), g.tiers, nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrStaffExcluded is reported when a match or rule would give software to a staff member excluded from it
+var ErrStaffExcluded = errors.New("staff member is excluded from this software")
+
+// ExclusionActive reports whether an exclusion is still in force at the given time
+func ExclusionActive(exclusion models.SoftwareExclusion, now time.Time) bool {
+	return exclusion.ExpiresAt == nil || exclusion.ExpiresAt.After(now)
+}
+
+// activeExclusions returns the exclusions in force as staff ID → excluded software IDs,
+// for a single software when softwareID is non-zero
+func activeExclusions(softwareID uint) (map[uint]map[uint]bool, error) {
+	var exclusions []models.SoftwareExclusion
+	query := config.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	if softwareID != 0 {
+		query = query.Where("software_id = ?", softwareID)
+	}
+	if err := query.Find(&exclusions).Error; err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uint]map[uint]bool)
+	for _, exclusion := range exclusions {
+		if excluded[exclusion.StaffID] == nil {
+			excluded[exclusion.StaffID] = make(map[uint]bool)
+		}
+		excluded[exclusion.StaffID][exclusion.SoftwareID] = true
+	}
+	return excluded, nil
+}
+
+// excludeAutoGranted excludes a staff member, inside the caller's transaction, from a software just
+// revoked from them when a match or rule was among its grants, so that it is not assigned again. It
+// returns the exclusion, or nil when only manual assignments were behind the software.
+func excludeAutoGranted(tx *gorm.DB, assignment models.AssignedSoftware, grants []models.AssignmentGrant, reason string) (*models.SoftwareExclusion, error) {
+	for _, grant := range grants {
+		if !isAutoGrant(grant) {
+			continue
+		}
+		exclusion := models.SoftwareExclusion{
+			StaffID:    assignment.StaffID,
+			SoftwareID: assignment.SoftwareID,
+			Reason:     reason,
+		}
+		if err := tx.Where("staff_id = ? AND software_id = ?", exclusion.StaffID, exclusion.SoftwareID).
+			FirstOrCreate(&exclusion).Error; err != nil {
+			return nil, err
+		}
+		return &exclusion, nil
+	}
+	return nil, nil
+}
+
+// ApplySoftwareExclusion drops the match and rule grants a newly excluded staff member holds for the
+// software, revoking it unless it was also assigned manually
+func ApplySoftwareExclusion(exclusion models.SoftwareExclusion) error {
+	if !ExclusionActive(exclusion, time.Now()) {
+		return nil
+	}
+	var assignment models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ? AND software_id = ?", exclusion.StaffID, exclusion.SoftwareID).
+		First(&assignment).Error; err != nil {
+		return nil
+	}
+	return removeGrants(assignment, isAutoGrant, ActionUnassigned)
+}
+
+// LiftSoftwareExclusion re-runs auto-assignment for a staff member whose exclusion was removed,
+// so that software their matches and rules grant is assigned again. Offboarded staff get nothing back.
+func LiftSoftwareExclusion(exclusion models.SoftwareExclusion) error {
+	var staff models.Staff
+	if err := config.DB.First(&staff, exclusion.StaffID).Error; err != nil {
+		return err
+	}
+	if strings.EqualFold(staff.Status, "inactive") {
+		return nil
+	}
+	return AutoAssignSoftwareToStaff(staff.ID, staff.DepartmentID, staff.TeamID)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// ProcessAssignmentExpiry expires time-bound assignments whose expiry has passed and warns holders of
+// those expiring within ASSIGNMENT_EXPIRY_NOTICE_DAYS (default 7). Only the manual grant expires:
+// software the staff member also holds through a match or rule is kept, without an expiry.
+func ProcessAssignmentExpiry() error {
+	now := time.Now()
+	lead := time.Duration(envDays("ASSIGNMENT_EXPIRY_NOTICE_DAYS", 7)) * oneDay
+
+	var assignments []models.AssignedSoftware
+	if err := config.DB.Where("expires_at IS NOT NULL AND expires_at <= ?", now.Add(lead)).
+		Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		if assignment.ExpiresAt.After(now) {
+			warnAssignmentExpiry(assignment, now)
+			continue
+		}
+		if err := expireAssignment(assignment); err != nil {
+			log.Printf("Failed to expire assignment %d: %v", assignment.ID, err)
+			continue
+		}
+		log.Printf("⌛ Assignment of software %d to staff %d expired", assignment.SoftwareID, assignment.StaffID)
+	}
+	return nil
+}
+
+// expireAssignment drops the manual grant of an expired assignment, revoking it with the Expired
+// action when nothing else grants the software
+func expireAssignment(assignment models.AssignedSoftware) error {
+	err := removeGrants(assignment, func(grant models.AssignmentGrant) bool {
+		return !isAutoGrant(grant)
+	}, ActionExpired)
+	if err != nil {
+		return err
+	}
+	// Kept through a match or rule: the assignment is no longer time-bound
+	return config.DB.Model(&models.AssignedSoftware{}).Where("id = ?", assignment.ID).
+		Update("expires_at", nil).Error
+}
+
+// warnAssignmentExpiry tells the holder of a time-bound assignment when it expires, once per expiry date
+func warnAssignmentExpiry(assignment models.AssignedSoftware, now time.Time) {
+	var staff models.StaffPlain
+	config.DB.Select("id", "email").First(&staff, assignment.StaffID)
+	var software models.Software
+	config.DB.Select("id", "name").First(&software, assignment.SoftwareID)
+
+	expiresAt := *assignment.ExpiresAt
+	fireReminder(models.Reminder{
+		Kind:     ReminderAssignmentExpiry,
+		DedupKey: fmt.Sprintf("%s:%d:%s", ReminderAssignmentExpiry, assignment.ID, expiresAt.Format("2006-01-02")),
+		DueDate:  expiresAt,
+		Message: fmt.Sprintf("%s access of %s expires on %s (in %d days)",
+			software.Name, staff.Email, expiresAt.Format("2006-01-02"), daysUntil(now, expiresAt)),
+	})
+}
+
+// FindExpiringAssignments lists time-bound assignments expiring within the window, soonest first.
+// Assignments already past their expiry that the job has not processed yet are included.
+func FindExpiringAssignments(within time.Duration) ([]models.ExpiringAssignment, error) {
+	now := time.Now()
+	expiring := []models.ExpiringAssignment{}
+
+	err := config.DB.Table("assigned_software").
+		Select("assigned_software.id AS assignment_id, assigned_software.staff_id, staff.email AS staff_email, "+
+			"assigned_software.software_id, software.name AS software, assigned_software.expires_at").
+		Joins("JOIN staff ON staff.id = assigned_software.staff_id").
+		Joins("JOIN software ON software.id = assigned_software.software_id").
+		Where("assigned_software.expires_at IS NOT NULL AND assigned_software.expires_at <= ?", now.Add(within)).
+		Order("assigned_software.expires_at").
+		Scan(&expiring).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range expiring {
+		expiring[i].DaysLeft = daysUntil(now, expiring[i].ExpiresAt)
+	}
+	return expiring, nil
+}
@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"log"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// Grant source types that are not also assignment sources
+const (
+	SourceManual        = "manual"
+	SourceAttributeRule = "attribute_rule"
+	SourceBundle        = "bundle"
+	SourceBundleMatch   = "bundle_match"
+)
+
+// grantKey identifies the match or rule behind a grant
+type grantKey struct {
+	SourceType string
+	SourceID   uint
+}
+
+// assignmentSource maps a grant onto the source column of assigned_software
+func assignmentSource(grant models.AssignmentGrant) string {
+	switch {
+	case grant.BundleID != nil:
+		return SourceBundle
+	case grant.SourceType == SourceAttributeRule:
+		return SourceRule
+	}
+	return grant.SourceType
+}
+
+// isAutoGrant reports whether a grant comes from a match or rule rather than a manual assignment
+func isAutoGrant(grant models.AssignmentGrant) bool {
+	return grant.SourceType != SourceManual
+}
+
+// HasAutoGrant reports whether a match or rule is among the grants behind an assignment
+func HasAutoGrant(assignment models.AssignedSoftware) (bool, error) {
+	grants, err := loadGrants(config.DB, assignment)
+	if err != nil {
+		return false, err
+	}
+	for _, grant := range grants {
+		if isAutoGrant(grant) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// grantMatches reports whether a recorded grant stands for the given source. Grants recorded before
+// sources were tracked carry no source ID and stand for any source of their type.
+func grantMatches(grant models.AssignmentGrant, key grantKey) bool {
+	return grant.SourceType == key.SourceType && (grant.SourceID == key.SourceID || grant.SourceID == 0)
+}
+
+// loadGrants returns the grants behind an assignment. An assignment without recorded grants is treated
+// as granted by its source column alone.
+func loadGrants(db *gorm.DB, assignment models.AssignedSoftware) ([]models.AssignmentGrant, error) {
+	var grants []models.AssignmentGrant
+	if err := db.Where("assigned_software_id = ?", assignment.ID).Order("id").Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		grants = append(grants, implicitGrant(assignment))
+	}
+	return grants, nil
+}
+
+// implicitGrant is the grant an assignment's source column stands for
+func implicitGrant(assignment models.AssignedSoftware) models.AssignmentGrant {
+	source := assignment.Source
+	if source == "" {
+		source = SourceManual
+	}
+	return models.AssignmentGrant{AssignedSoftwareID: assignment.ID, SourceType: source, PlanID: assignment.PlanID}
+}
+
+// AddGrant records another grant behind an existing assignment. A grant that is already recorded
+// only has its plan updated.
+func AddGrant(assignmentID uint, grant models.AssignmentGrant) error {
+	return addGrant(config.DB, assignmentID, grant)
+}
+
+func addGrant(db *gorm.DB, assignmentID uint, grant models.AssignmentGrant) error {
+	var existing models.AssignmentGrant
+	err := db.
+		Where("assigned_software_id = ? AND source_type = ? AND source_id = ?", assignmentID, grant.SourceType, grant.SourceID).
+		First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		grant.ID = 0
+		grant.AssignedSoftwareID = assignmentID
+		return db.Create(&grant).Error
+	}
+	if err != nil {
+		return err
+	}
+	return db.Model(&existing).Update("plan_id", grant.PlanID).Error
+}
+
+// removeGrants drops the grants selected by drop from an assignment. The assignment itself is revoked
+// with the given action once no grant is left; otherwise its source moves to a remaining grant.
+func removeGrants(assignment models.AssignedSoftware, drop func(models.AssignmentGrant) bool, action string) error {
+	grants, err := loadGrants(config.DB, assignment)
+	if err != nil {
+		return err
+	}
+
+	var kept []models.AssignmentGrant
+	var dropped []uint
+	for _, grant := range grants {
+		if drop(grant) {
+			if grant.ID != 0 {
+				dropped = append(dropped, grant.ID)
+			}
+			continue
+		}
+		kept = append(kept, grant)
+	}
+	if len(kept) == len(grants) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return RevokeAssignment(assignment, action)
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(dropped) > 0 {
+			if err := tx.Delete(&models.AssignmentGrant{}, dropped).Error; err != nil {
+				return err
+			}
+		}
+		source := assignmentSource(kept[0])
+		if source == assignment.Source {
+			return nil
+		}
+		return tx.Model(&assignment).Update("source", source).Error
+	})
+}
+
+// revokeGrant removes the grant a single match or rule gave a staff member for a software. The
+// software is only revoked when that was its last grant.
+func revokeGrant(staffID, softwareID uint, sourceType string, sourceID uint, action string) {
+	var assignment models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ? AND software_id = ?", staffID, softwareID).First(&assignment).Error; err != nil {
+		return
+	}
+
+	key := grantKey{SourceType: sourceType, SourceID: sourceID}
+	err := removeGrants(assignment, func(grant models.AssignmentGrant) bool {
+		return grantMatches(grant, key)
+	}, action)
+	if err != nil {
+		log.Printf("Failed to revoke software %d from staff %d: %v", softwareID, staffID, err)
+	}
+}
+
+// revokeUngrantedAccess drops every match and rule grant of a staff member that no match or rule
+// grants any more, e.g. after they moved to another department or team, and revokes software left
+// without any grant. Manual grants are kept.
+func revokeUngrantedAccess(staffID, departmentID, teamID uint) {
+	entitlements, _, err := resolveEntitlements(staffID, departmentID, teamID)
+	if err != nil {
+		log.Printf("Failed to resolve entitlements of staff %d: %v", staffID, err)
+		return
+	}
+	granted := entitledGrants(entitlements)
+
+	var assignments []models.AssignedSoftware
+	config.DB.Where("staff_id = ?", staffID).Find(&assignments)
+	for _, assignment := range assignments {
+		current := granted[assignment.SoftwareID]
+		err := removeGrants(assignment, func(grant models.AssignmentGrant) bool {
+			return isAutoGrant(grant) && !grantStillEntitled(grant, current)
+		}, ActionUnassigned)
+		if err != nil {
+			log.Printf("Failed to revoke software %d from staff %d: %v", assignment.SoftwareID, staffID, err)
+		}
+	}
+}
+
+// entitledGrants indexes the grants behind a staff member's entitlements by software
+func entitledGrants(entitlements []entitlement) map[uint][]models.AssignmentGrant {
+	granted := make(map[uint][]models.AssignmentGrant)
+	for _, e := range entitlements {
+		granted[e.SoftwareID] = e.Grants
+	}
+	return granted
+}
+
+// grantStillEntitled reports whether a recorded grant is among the grants a staff member is entitled to
+func grantStillEntitled(grant models.AssignmentGrant, entitled []models.AssignmentGrant) bool {
+	for _, e := range entitled {
+		if grantMatches(grant, grantKey{SourceType: e.SourceType, SourceID: e.SourceID}) {
+			return true
+		}
+	}
+	return false
+}
+
+// BackfillAssignmentGrants records the grants behind assignments made before grants were tracked.
+// Each gets every match and rule that currently grants it, and its recorded source when none does.
+func BackfillAssignmentGrants() error {
+	var assignments []models.AssignedSoftware
+	if err := config.DB.
+		Where("NOT EXISTS (SELECT 1 FROM assignment_grants WHERE assignment_grants.assigned_software_id = assigned_software.id)").
+		Find(&assignments).Error; err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	sources, err := loadGrantSources()
+	if err != nil {
+		return err
+	}
+
+	entitled := make(map[uint]map[uint][]models.AssignmentGrant)
+	for _, assignment := range assignments {
+		if _, ok := entitled[assignment.StaffID]; !ok {
+			var staff models.Staff
+			if err := config.DB.Preload("Department").Preload("Team").First(&staff, assignment.StaffID).Error; err != nil {
+				entitled[assignment.StaffID] = nil
+			} else {
+				entitled[assignment.StaffID] = entitledGrants(
+					sources.resolve(staff.ID, staff.DepartmentID, staff.TeamID, StaffAttributes(staff)))
+			}
+		}
+
+		var grants []models.AssignmentGrant
+		recorded := false
+		for _, grant := range entitled[assignment.StaffID][assignment.SoftwareID] {
+			if assignmentSource(grant) == assignment.Source {
+				recorded = true
+			}
+			grants = append(grants, grant)
+		}
+		if !recorded {
+			grants = append(grants, implicitGrant(assignment))
+		}
+
+		for _, grant := range grants {
+			if err := AddGrant(assignment.ID, grant); err != nil {
+				return err
+			}
+		}
+	}
+	log.Printf("Recorded grants for %d existing assignments", len(assignments))
+	return nil
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoLicenseKeyAvailable is returned when a key-based software has no unallocated keys left
+var ErrNoLicenseKeyAvailable = errors.New("no free license key available for this software")
+
+// AddLicenseKey encrypts a plaintext key and stores it in the software's pool
+func AddLicenseKey(softwareID uint, label, plaintext string) (models.LicenseKey, error) {
+	encrypted, err := EncryptSecret(plaintext)
+	if err != nil {
+		return models.LicenseKey{}, err
+	}
+
+	key := models.LicenseKey{
+		SoftwareID:   softwareID,
+		Label:        label,
+		EncryptedKey: encrypted,
+		KeyHint:      keyHint(plaintext),
+	}
+	if err := config.DB.Create(&key).Error; err != nil {
+		return models.LicenseKey{}, err
+	}
+	return key, nil
+}
+
+// RevealLicenseKey decrypts a key and writes an access log entry for the audit trail.
+// Nothing is returned unless the access log was stored.
+func RevealLicenseKey(key models.LicenseKey, requestedBy uint, reason, clientIP string) (string, error) {
+	plaintext, err := DecryptSecret(key.EncryptedKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := config.DB.Create(&models.LicenseKeyAccessLog{
+		LicenseKeyID: key.ID,
+		SoftwareID:   key.SoftwareID,
+		RequestedBy:  requestedBy,
+		Reason:       reason,
+		ClientIP:     clientIP,
+		AccessedAt:   time.Now(),
+	}).Error; err != nil {
+		return "", err
+	}
+	return plaintext, nil
+}
+
+// ReleaseLicenseKey returns the key held by an assignment to the pool
+func ReleaseLicenseKey(assignmentID uint) error {
+	return releaseLicenseKey(config.DB, assignmentID)
+}
+
+// allocateLicenseKey gives a new assignment a free key if its software is key-based.
+// Software without any keys in the vault is not key-based and needs nothing.
+func allocateLicenseKey(tx *gorm.DB, assignment *models.AssignedSoftware) error {
+	var total int64
+	if err := tx.Model(&models.LicenseKey{}).Where("software_id = ?", assignment.SoftwareID).Count(&total).Error; err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// Lock the free key, and only take it if it is still free, so that concurrent assignments
+	// never share a key
+	var key models.LicenseKey
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("software_id = ? AND assigned_software_id IS NULL", assignment.SoftwareID).
+		Order("id ASC").First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoLicenseKeyAvailable
+		}
+		return err
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.LicenseKey{}).
+		Where("id = ? AND assigned_software_id IS NULL", key.ID).
+		Updates(map[string]interface{}{
+			"assigned_software_id": assignment.ID,
+			"allocated_at":         now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoLicenseKeyAvailable
+	}
+	return nil
+}
+
+// releaseLicenseKey clears the allocation of any key held by the assignment
+func releaseLicenseKey(tx *gorm.DB, assignmentID uint) error {
+	return tx.Model(&models.LicenseKey{}).
+		Where("assigned_software_id = ?", assignmentID).
+		Updates(map[string]interface{}{
+			"assigned_software_id": nil,
+			"allocated_at":         nil,
+		}).Error
+}
+
+// keyHint masks all but the last four characters of a key
+func keyHint(plaintext string) string {
+	if len(plaintext) <= 4 {
+		return "****"
+	}
+	return "****" + plaintext[len(plaintext)-4:]
+}
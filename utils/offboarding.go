@@ -0,0 +1,476 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// Constants for offboardings and the deprovisioning of their items
+const (
+	OffboardingScheduled  = "scheduled"
+	OffboardingInProgress = "in_progress"
+	OffboardingCompleted  = "completed"
+	OffboardingCancelled  = "cancelled"
+
+	HandleRevoke   = "revoke"
+	HandleTransfer = "transfer"
+	HandleKeep     = "keep"
+
+	SourceAuto = "auto"
+
+	ItemRevoked     = "revoked"
+	ItemTransferred = "transferred"
+	ItemKept        = "kept"
+)
+
+var (
+	// ErrOffboardingOpen is returned when scheduling an offboarding for staff who already have one under way
+	ErrOffboardingOpen = errors.New("staff member already has an offboarding scheduled or in progress")
+	// ErrOffboardingClosed is returned when changing an offboarding that is completed or cancelled
+	ErrOffboardingClosed = errors.New("offboarding is already completed or cancelled")
+	// ErrOffboardingStarted is returned when cancelling an offboarding that has already deprovisioned software
+	ErrOffboardingStarted = errors.New("offboarding has already started deprovisioning software")
+	// ErrOffboardingNotCompleted is returned when asking for the certificate of an unfinished offboarding
+	ErrOffboardingNotCompleted = errors.New("offboarding is not completed yet")
+	// ErrItemProcessed is returned when replanning software that has already been deprovisioned
+	ErrItemProcessed = errors.New("software has already been deprovisioned")
+	// ErrUnbackedOwnership is returned when running an offboarding that is not forced while the leaver
+	// owns software with no active backup owner
+	ErrUnbackedOwnership = errors.New("staff member still owns software with no active backup owner")
+)
+
+// NormalizeHandling maps how a manually assigned software is handled onto its canonical spelling,
+// defaulting to revoke
+func NormalizeHandling(handling string) (string, bool) {
+	switch handling = strings.ToLower(strings.TrimSpace(handling)); handling {
+	case "":
+		return HandleRevoke, true
+	case HandleRevoke, HandleTransfer, HandleKeep:
+		return handling, true
+	}
+	return "", false
+}
+
+// offboardingOpen reports whether an offboarding can still be changed and run
+func offboardingOpen(status string) bool {
+	return status == OffboardingScheduled || status == OffboardingInProgress
+}
+
+// ScheduleOffboarding plans the deprovisioning of everything a staff member holds for their last
+// day. Manually assigned software follows the offboarding's default handling unless an override names
+// it; software granted only by matches and rules is always revoked. An offboarding effective now or
+// earlier runs straight away.
+func ScheduleOffboarding(offboarding *models.Offboarding, overrides []models.OffboardingAction) error {
+	var open int64
+	if err := config.DB.Model(&models.Offboarding{}).
+		Where("staff_id = ? AND status IN ?", offboarding.StaffID, []string{OffboardingScheduled, OffboardingInProgress}).
+		Count(&open).Error; err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrOffboardingOpen
+	}
+
+	offboarding.Status = OffboardingScheduled
+	items, err := planOffboardingItems(*offboarding, nil, overrides)
+	if err != nil {
+		return err
+	}
+	offboarding.Items = items
+	if err := config.DB.Create(offboarding).Error; err != nil {
+		return err
+	}
+
+	if !offboarding.EffectiveDate.After(time.Now()) {
+		return ExecuteOffboarding(offboarding)
+	}
+	return nil
+}
+
+// planOffboardingItems plans an item for every assignment of the leaver not covered by planned yet
+func planOffboardingItems(offboarding models.Offboarding, planned []models.OffboardingItem, overrides []models.OffboardingAction) ([]models.OffboardingItem, error) {
+	covered := make(map[uint]bool, len(planned))
+	for _, item := range planned {
+		covered[item.AssignedSoftwareID] = true
+	}
+	override := make(map[uint]models.OffboardingAction, len(overrides))
+	for _, o := range overrides {
+		override[o.SoftwareID] = o
+	}
+
+	var assignments []models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ?", offboarding.StaffID).Order("id").Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	softwareIDs := make([]uint, len(assignments))
+	for i, assignment := range assignments {
+		softwareIDs[i] = assignment.SoftwareID
+	}
+	names := softwareNames(config.DB, softwareIDs)
+
+	var items []models.OffboardingItem
+	for _, assignment := range assignments {
+		if covered[assignment.ID] {
+			continue
+		}
+		manual, err := HasManualGrant(assignment)
+		if err != nil {
+			return nil, err
+		}
+		item := models.OffboardingItem{
+			OffboardingID:      offboarding.ID,
+			AssignedSoftwareID: assignment.ID,
+			SoftwareID:         assignment.SoftwareID,
+			Software:           names[assignment.SoftwareID],
+			Source:             SourceAuto,
+			Action:             HandleRevoke,
+			Status:             ItemPending,
+		}
+		if manual {
+			item.Source = SourceManual
+			item.Action = offboarding.ManualHandling
+			item.TransferToID = offboarding.TransferToID
+			item.Reason = offboarding.Reason
+			if o, ok := override[assignment.SoftwareID]; ok {
+				item.Action, item.TransferToID, item.Reason = o.Action, o.TransferToID, o.Reason
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// keptAssignments returns the IDs of the assignments offboardings left with their leavers on purpose
+func keptAssignments() (map[uint]bool, error) {
+	var ids []uint
+	if err := config.DB.Model(&models.OffboardingItem{}).Where("status = ?", ItemKept).
+		Pluck("assigned_software_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	kept := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		kept[id] = true
+	}
+	return kept, nil
+}
+
+// HasManualGrant reports whether a manual assignment is among the grants behind an assignment
+func HasManualGrant(assignment models.AssignedSoftware) (bool, error) {
+	grants, err := loadGrants(config.DB, assignment)
+	if err != nil {
+		return false, err
+	}
+	for _, grant := range grants {
+		if !isAutoGrant(grant) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReplanOffboardingItem changes what is done with a software that has not been deprovisioned yet
+func ReplanOffboardingItem(item *models.OffboardingItem, action string, transferToID *uint, reason string) error {
+	var offboarding models.Offboarding
+	if err := config.DB.First(&offboarding, item.OffboardingID).Error; err != nil {
+		return err
+	}
+	if !offboardingOpen(offboarding.Status) {
+		return ErrOffboardingClosed
+	}
+	if item.Status != ItemPending && item.Status != ItemFailed {
+		return ErrItemProcessed
+	}
+
+	item.Action = action
+	item.TransferToID = transferToID
+	item.Reason = reason
+	return config.DB.Save(item).Error
+}
+
+// ExecuteOffboarding deprovisions every software of an open offboarding that is still pending or
+// failed, after planning software the leaver gained since it was scheduled. The leaver is marked
+// inactive and backup owners take over their software. The offboarding completes once no item has
+// failed; otherwise it stays in progress and failed items are retried on the next run. Unless it is
+// forced, an offboarding is held, with nothing changed, while the leaver owns software without an
+// active backup owner, as ownership may have changed since it was scheduled.
+func ExecuteOffboarding(offboarding *models.Offboarding) error {
+	if !offboardingOpen(offboarding.Status) {
+		return ErrOffboardingClosed
+	}
+	if !offboarding.Forced {
+		unbacked, err := UnbackedSoftware(offboarding.StaffID)
+		if err != nil {
+			return err
+		}
+		if len(unbacked) > 0 {
+			return ErrUnbackedOwnership
+		}
+	}
+	if err := config.DB.Where("offboarding_id = ?", offboarding.ID).Order("id").Find(&offboarding.Items).Error; err != nil {
+		return err
+	}
+	added, err := planOffboardingItems(*offboarding, offboarding.Items, nil)
+	if err != nil {
+		return err
+	}
+	if len(added) > 0 {
+		if err := config.DB.Create(&added).Error; err != nil {
+			return err
+		}
+		offboarding.Items = append(offboarding.Items, added...)
+	}
+
+	failed := false
+	for i := range offboarding.Items {
+		item := &offboarding.Items[i]
+		if item.Status != ItemPending && item.Status != ItemFailed {
+			continue
+		}
+		deprovisionItem(offboarding.StaffID, item)
+		if err := config.DB.Save(item).Error; err != nil {
+			return err
+		}
+		if item.Status == ItemFailed {
+			failed = true
+		}
+	}
+
+	if err := config.DB.Model(&models.Staff{}).Where("id = ?", offboarding.StaffID).
+		Update("status", "inactive").Error; err != nil {
+		log.Println("Failed to mark staff inactive during offboarding:", err)
+	}
+	if err := HandOverOwnership(offboarding.StaffID); err != nil {
+		log.Println("Failed to hand software ownership over to backup owners:", err)
+	}
+
+	offboarding.Status = OffboardingInProgress
+	offboarding.CompletedAt = nil
+	if !failed {
+		now := time.Now()
+		offboarding.Status = OffboardingCompleted
+		offboarding.CompletedAt = &now
+	}
+	return config.DB.Model(&models.Offboarding{}).Where("id = ?", offboarding.ID).
+		Updates(map[string]interface{}{"status": offboarding.Status, "completed_at": offboarding.CompletedAt}).Error
+}
+
+// deprovisionItem carries out the planned action for one software of a leaver and records the outcome
+func deprovisionItem(staffID uint, item *models.OffboardingItem) {
+	now := time.Now()
+	item.ProcessedAt = &now
+	item.Detail = ""
+
+	var assignment models.AssignedSoftware
+	if err := config.DB.First(&assignment, item.AssignedSoftwareID).Error; err != nil || assignment.StaffID != staffID {
+		item.Status = ItemRevoked
+		item.Detail = "Already unassigned before the offboarding ran"
+		return
+	}
+
+	var err error
+	switch item.Action {
+	case HandleKeep:
+		// Only the manual assignment survives; matches and rules no longer apply to a leaver
+		if err = removeGrants(assignment, isAutoGrant, ActionOffboarded); err == nil {
+			item.Status = ItemKept
+		}
+	case HandleTransfer:
+		if item.TransferToID == nil {
+			item.Status = ItemFailed
+			item.Detail = "No recipient to transfer the software to"
+			item.ProcessedAt = nil
+			return
+		}
+		var held bool
+		if held, err = transferAssignment(assignment, *item.TransferToID); err == nil {
+			item.Status = ItemTransferred
+			if held {
+				item.Status = ItemRevoked
+				item.Detail = "The recipient already had this software"
+			}
+		}
+	default:
+		if err = RevokeAssignment(assignment, ActionOffboarded); err == nil {
+			item.Status = ItemRevoked
+		}
+	}
+	if err != nil {
+		item.Status = ItemFailed
+		item.Detail = err.Error()
+		item.ProcessedAt = nil
+	}
+}
+
+// transferAssignment hands a leaver's assignment, with its seat, plan and license key, to another
+// staff member as a manual assignment. The transfer is subject to the recipient's software policies
+// and department budget. When the recipient already holds the software the leaver's assignment is
+// revoked instead, and held is true.
+func transferAssignment(assignment models.AssignedSoftware, recipientID uint) (held bool, err error) {
+	var existing int64
+	if err := config.DB.Model(&models.AssignedSoftware{}).
+		Where("staff_id = ? AND software_id = ?", recipientID, assignment.SoftwareID).Count(&existing).Error; err != nil {
+		return false, err
+	}
+	if existing > 0 {
+		return true, RevokeAssignment(assignment, ActionOffboarded)
+	}
+
+	moved := assignment
+	moved.StaffID = recipientID
+	var overBudget *models.Department
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkPolicies(tx, recipientID, assignment.SoftwareID); err != nil {
+			return err
+		}
+		var err error
+		if overBudget, err = budgetOverrun(tx, &moved); err != nil {
+			return err
+		}
+		if err := tx.Where("assigned_software_id = ?", assignment.ID).Delete(&models.AssignmentGrant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.AssignmentGrant{AssignedSoftwareID: assignment.ID, SourceType: SourceManual, PlanID: assignment.PlanID}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.AssignedSoftware{}).Where("id = ?", assignment.ID).Updates(map[string]interface{}{
+			"staff_id":     recipientID,
+			"source":       SourceManual,
+			"assigned_at":  time.Now(),
+			"last_used_at": nil,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	if overBudget != nil {
+		alertOverBudget(*overBudget, moved)
+	}
+	if assignment.Status != AssignmentPending {
+		logAssignmentChange(assignment.StaffID, assignment.SoftwareID, ActionOffboarded)
+		logAssignmentChange(recipientID, assignment.SoftwareID, ActionAssigned)
+	}
+	return false, nil
+}
+
+// CancelOffboarding calls off a scheduled offboarding before anything has been deprovisioned
+func CancelOffboarding(offboarding *models.Offboarding) error {
+	result := config.DB.Model(&models.Offboarding{}).
+		Where("id = ? AND status = ?", offboarding.ID, OffboardingScheduled).
+		Update("status", OffboardingCancelled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if offboarding.Status == OffboardingInProgress {
+			return ErrOffboardingStarted
+		}
+		return ErrOffboardingClosed
+	}
+	offboarding.Status = OffboardingCancelled
+	return nil
+}
+
+// ProcessDueOffboardings runs the offboardings whose effective date has come, and retries those still
+// in progress
+func ProcessDueOffboardings() error {
+	var offboardings []models.Offboarding
+	if err := config.DB.Where("status IN ? AND effective_date <= ?", []string{OffboardingScheduled, OffboardingInProgress}, time.Now()).
+		Find(&offboardings).Error; err != nil {
+		return err
+	}
+
+	for i := range offboardings {
+		offboarding := &offboardings[i]
+		if err := ExecuteOffboarding(offboarding); err != nil {
+			if errors.Is(err, ErrUnbackedOwnership) {
+				log.Printf("Offboarding %d of staff %d held: %v", offboarding.ID, offboarding.StaffID, err)
+				continue
+			}
+			log.Printf("Offboarding %d of staff %d failed: %v", offboarding.ID, offboarding.StaffID, err)
+			continue
+		}
+		if offboarding.Status == OffboardingCompleted {
+			log.Printf("🚪 Offboarded staff %d", offboarding.StaffID)
+		} else {
+			log.Printf("Offboarding of staff %d left software to retry", offboarding.StaffID)
+		}
+	}
+	return nil
+}
+
+// IssueOffboardingCertificate lists everything a completed offboarding revoked, transferred and kept,
+// with the time each was processed
+func IssueOffboardingCertificate(offboarding models.Offboarding) (models.OffboardingCertificate, error) {
+	certificate := models.OffboardingCertificate{
+		OffboardingID: offboarding.ID,
+		StaffID:       offboarding.StaffID,
+		EffectiveDate: offboarding.EffectiveDate,
+		Revoked:       []models.OffboardingCertificateEntry{},
+		Transferred:   []models.OffboardingCertificateEntry{},
+		Kept:          []models.OffboardingCertificateEntry{},
+		IssuedAt:      time.Now(),
+	}
+	if offboarding.Status != OffboardingCompleted || offboarding.CompletedAt == nil {
+		return certificate, ErrOffboardingNotCompleted
+	}
+	certificate.CompletedAt = *offboarding.CompletedAt
+
+	var staff models.StaffPlain
+	if err := config.DB.First(&staff, offboarding.StaffID).Error; err != nil {
+		return certificate, err
+	}
+	certificate.StaffName = strings.TrimSpace(staff.FirstName + " " + staff.LastName)
+	certificate.StaffEmail = staff.Email
+
+	var items []models.OffboardingItem
+	if err := config.DB.Where("offboarding_id = ?", offboarding.ID).Order("processed_at, id").Find(&items).Error; err != nil {
+		return certificate, err
+	}
+	var recipientIDs []uint
+	for _, item := range items {
+		if item.Status == ItemTransferred && item.TransferToID != nil {
+			recipientIDs = append(recipientIDs, *item.TransferToID)
+		}
+	}
+	emails := make(map[uint]string)
+	if len(recipientIDs) > 0 {
+		var recipients []models.StaffPlain
+		config.DB.Select("id", "email").Where("id IN ?", recipientIDs).Find(&recipients)
+		for _, recipient := range recipients {
+			emails[recipient.ID] = recipient.Email
+		}
+	}
+
+	for _, item := range items {
+		entry := models.OffboardingCertificateEntry{
+			SoftwareID: item.SoftwareID,
+			Software:   item.Software,
+			Source:     item.Source,
+			Detail:     item.Detail,
+		}
+		if item.ProcessedAt != nil {
+			entry.ProcessedAt = *item.ProcessedAt
+		}
+		switch item.Status {
+		case ItemRevoked:
+			certificate.Revoked = append(certificate.Revoked, entry)
+		case ItemTransferred:
+			entry.TransferToID = item.TransferToID
+			if item.TransferToID != nil {
+				entry.TransferToEmail = emails[*item.TransferToID]
+			}
+			certificate.Transferred = append(certificate.Transferred, entry)
+		case ItemKept:
+			entry.Reason = item.Reason
+			certificate.Kept = append(certificate.Kept, entry)
+		}
+	}
+	return certificate, nil
+}
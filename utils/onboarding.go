@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// Constants for onboardings and their items
+const (
+	OnboardingOpen      = "open"
+	OnboardingCompleted = "completed"
+
+	OnboardingSoftware = "software"
+	OnboardingTask     = "task"
+
+	ItemPending     = "pending"
+	ItemProvisioned = "provisioned"
+	ItemFailed      = "failed"
+	ItemSkipped     = "skipped"
+	ItemDone        = "done"
+)
+
+// ErrOnboardingIncomplete is returned when closing an onboarding with items still pending or failed
+var ErrOnboardingIncomplete = errors.New("onboarding still has pending or failed items")
+
+// ErrOnboardingClosed is returned when changing an onboarding that is already completed
+var ErrOnboardingClosed = errors.New("onboarding is already completed")
+
+// ItemResolved reports whether an onboarding item needs no more work
+func ItemResolved(status string) bool {
+	return status == ItemProvisioned || status == ItemDone || status == ItemSkipped
+}
+
+// defaultOnboardingTasks lists the manual tasks every onboarding starts with, from the
+// semicolon-separated ONBOARDING_TASKS setting
+func defaultOnboardingTasks() []string {
+	var tasks []string
+	for _, task := range strings.Split(os.Getenv("ONBOARDING_TASKS"), ";") {
+		if task = strings.TrimSpace(task); task != "" {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// StartOnboarding auto-assigns the software a new staff member is entitled to and records the outcome
+// of each, together with the default manual tasks, in their onboarding
+func StartOnboarding(staff models.Staff) (models.Onboarding, error) {
+	onboarding := models.Onboarding{StaffID: staff.ID, Status: OnboardingOpen}
+
+	outcomes, err := autoAssignSoftware(staff.ID, staff.DepartmentID, staff.TeamID)
+	if err != nil {
+		return onboarding, err
+	}
+	names := softwareNames(config.DB, outcomeSoftwareIDs(outcomes))
+	for _, outcome := range outcomes {
+		softwareID := outcome.SoftwareID
+		item := models.OnboardingItem{Kind: OnboardingSoftware, SoftwareID: &softwareID, Title: names[softwareID]}
+		setSoftwareItemStatus(&item, staff.ID, outcome.Err)
+		onboarding.Items = append(onboarding.Items, item)
+	}
+	for _, task := range defaultOnboardingTasks() {
+		onboarding.Items = append(onboarding.Items, models.OnboardingItem{Kind: OnboardingTask, Title: task, Status: ItemPending})
+	}
+
+	if err := config.DB.Create(&onboarding).Error; err != nil {
+		return onboarding, err
+	}
+	return onboarding, closeIfResolved(&onboarding)
+}
+
+// SyncOnboarding retries the software of an open onboarding that is not provisioned yet and adds
+// software the staff member became entitled to since it started
+func SyncOnboarding(onboarding *models.Onboarding) error {
+	if onboarding.Status != OnboardingOpen {
+		return ErrOnboardingClosed
+	}
+	var staff models.Staff
+	if err := config.DB.First(&staff, onboarding.StaffID).Error; err != nil {
+		return err
+	}
+	outcomes, err := autoAssignSoftware(staff.ID, staff.DepartmentID, staff.TeamID)
+	if err != nil {
+		return err
+	}
+
+	items := make(map[uint]*models.OnboardingItem)
+	for i := range onboarding.Items {
+		if item := &onboarding.Items[i]; item.Kind == OnboardingSoftware && item.SoftwareID != nil {
+			items[*item.SoftwareID] = item
+		}
+	}
+	names := softwareNames(config.DB, outcomeSoftwareIDs(outcomes))
+	for _, outcome := range outcomes {
+		item, ok := items[outcome.SoftwareID]
+		if !ok {
+			softwareID := outcome.SoftwareID
+			item = &models.OnboardingItem{OnboardingID: onboarding.ID, Kind: OnboardingSoftware, SoftwareID: &softwareID, Title: names[softwareID]}
+		} else if item.Status == ItemProvisioned || item.Status == ItemSkipped {
+			continue
+		}
+		setSoftwareItemStatus(item, staff.ID, outcome.Err)
+		if err := config.DB.Save(item).Error; err != nil {
+			return err
+		}
+		if !ok {
+			onboarding.Items = append(onboarding.Items, *item)
+		}
+	}
+	return closeIfResolved(onboarding)
+}
+
+// UpdateOnboardingItem records the progress of an item by hand: tasks are done, skipped or pending
+// again, software can only be skipped, since it is provisioned by assigning it. The onboarding
+// closes once its last item is resolved.
+func UpdateOnboardingItem(item *models.OnboardingItem, status, detail string) error {
+	var onboarding models.Onboarding
+	if err := config.DB.First(&onboarding, item.OnboardingID).Error; err != nil {
+		return err
+	}
+	if onboarding.Status != OnboardingOpen {
+		return ErrOnboardingClosed
+	}
+
+	item.Status = status
+	item.Detail = detail
+	item.CompletedAt = nil
+	if ItemResolved(status) {
+		now := time.Now()
+		item.CompletedAt = &now
+	}
+	if err := config.DB.Save(item).Error; err != nil {
+		return err
+	}
+	if err := config.DB.Preload("Items").First(&onboarding, onboarding.ID).Error; err != nil {
+		return err
+	}
+	return closeIfResolved(&onboarding)
+}
+
+// CompleteOnboarding closes an onboarding, which is only allowed once every item is resolved
+func CompleteOnboarding(onboarding *models.Onboarding) error {
+	if onboarding.Status != OnboardingOpen {
+		return ErrOnboardingClosed
+	}
+	for _, item := range onboarding.Items {
+		if !ItemResolved(item.Status) {
+			return ErrOnboardingIncomplete
+		}
+	}
+	return closeIfResolved(onboarding)
+}
+
+// markOnboardingProvisioned flags the software of a staff member's open onboarding as provisioned,
+// e.g. once a pending assignment activates on their start date
+func markOnboardingProvisioned(staffID, softwareID uint) {
+	var onboarding models.Onboarding
+	if err := config.DB.Preload("Items").
+		Where("staff_id = ? AND status = ?", staffID, OnboardingOpen).First(&onboarding).Error; err != nil {
+		return
+	}
+	now := time.Now()
+	for i := range onboarding.Items {
+		item := &onboarding.Items[i]
+		if item.Kind == OnboardingSoftware && item.SoftwareID != nil && *item.SoftwareID == softwareID {
+			item.Status = ItemProvisioned
+			item.Detail = ""
+			item.CompletedAt = &now
+			config.DB.Save(item)
+		}
+	}
+	closeIfResolved(&onboarding)
+}
+
+// setSoftwareItemStatus derives the status of a software item from the outcome of assigning it.
+// Software refused by a conflict or requires policy is skipped; any other refusal is a failure
+// that can be retried.
+func setSoftwareItemStatus(item *models.OnboardingItem, staffID uint, err error) {
+	item.CompletedAt = nil
+	if err != nil {
+		item.Status = ItemFailed
+		if errors.Is(err, ErrPolicyViolation) {
+			item.Status = ItemSkipped
+		}
+		item.Detail = err.Error()
+		return
+	}
+
+	var assignment models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ? AND software_id = ?", staffID, *item.SoftwareID).
+		First(&assignment).Error; err == nil && assignment.Status == AssignmentPending && assignment.StartsAt != nil {
+		item.Status = ItemPending
+		item.Detail = "Activates on the start date, " + assignment.StartsAt.Format("2006-01-02")
+		return
+	}
+	now := time.Now()
+	item.Status = ItemProvisioned
+	item.Detail = ""
+	item.CompletedAt = &now
+}
+
+// closeIfResolved completes an open onboarding whose items are all resolved
+func closeIfResolved(onboarding *models.Onboarding) error {
+	if onboarding.Status != OnboardingOpen {
+		return nil
+	}
+	for _, item := range onboarding.Items {
+		if !ItemResolved(item.Status) {
+			return nil
+		}
+	}
+	now := time.Now()
+	onboarding.Status = OnboardingCompleted
+	onboarding.CompletedAt = &now
+	return config.DB.Model(&models.Onboarding{}).Where("id = ?", onboarding.ID).
+		Updates(map[string]interface{}{"status": OnboardingCompleted, "completed_at": now}).Error
+}
+
+func outcomeSoftwareIDs(outcomes []assignOutcome) []uint {
+	ids := make([]uint, len(outcomes))
+	for i, outcome := range outcomes {
+		ids[i] = outcome.SoftwareID
+	}
+	return ids
+}
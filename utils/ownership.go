@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"software_management/config"
+	"software_management/models"
+)
+
+// Constants for software owner roles
+const (
+	RoleOwner       = "owner"
+	RoleBackupOwner = "backup_owner"
+)
+
+// activeStaffIDs is a subquery of the staff who have not been offboarded
+const activeStaffIDs = "SELECT id FROM staff WHERE COALESCE(LOWER(status), '') <> 'inactive'"
+
+// FindOwnedSoftware lists the software a staff member owns or backs up, by name
+func FindOwnedSoftware(staffID uint) ([]models.OwnedSoftware, error) {
+	var software []models.Software
+	if err := config.DB.Where("owner_id = ? OR backup_owner_id = ?", staffID, staffID).
+		Order("name").Find(&software).Error; err != nil {
+		return nil, err
+	}
+
+	owned := make([]models.OwnedSoftware, 0, len(software))
+	for _, sw := range software {
+		role := RoleBackupOwner
+		if sw.OwnerID != nil && *sw.OwnerID == staffID {
+			role = RoleOwner
+		}
+		owned = append(owned, models.OwnedSoftware{Software: sw, Role: role})
+	}
+	return owned, nil
+}
+
+// UnbackedSoftware lists the software a staff member owns without an active backup owner to take over
+func UnbackedSoftware(staffID uint) ([]models.Software, error) {
+	var software []models.Software
+	err := config.DB.
+		Where("owner_id = ? AND (backup_owner_id IS NULL OR backup_owner_id NOT IN ("+activeStaffIDs+"))", staffID).
+		Order("name").Find(&software).Error
+	return software, err
+}
+
+// HandOverOwnership is run when a staff member leaves: active backup owners become the owners of the
+// software the staff member owned, and the staff member stops backing up other software. Software
+// without an active backup keeps the leaver as owner until it is reassigned.
+func HandOverOwnership(staffID uint) error {
+	var software []models.Software
+	if err := config.DB.
+		Where("owner_id = ? AND backup_owner_id IN ("+activeStaffIDs+")", staffID).
+		Find(&software).Error; err != nil {
+		return err
+	}
+	for _, sw := range software {
+		if err := config.DB.Model(&models.Software{}).Where("id = ?", sw.ID).
+			Updates(map[string]interface{}{"owner_id": *sw.BackupOwnerID, "backup_owner_id": nil}).Error; err != nil {
+			return err
+		}
+	}
+
+	return config.DB.Model(&models.Software{}).Where("backup_owner_id = ?", staffID).
+		Update("backup_owner_id", nil).Error
+}
+
+// ReleaseOwnership hands over the software of a deleted staff member and clears them as owner of the rest
+func ReleaseOwnership(staffID uint) error {
+	if err := HandOverOwnership(staffID); err != nil {
+		return err
+	}
+	return config.DB.Model(&models.Software{}).Where("owner_id = ?", staffID).
+		Update("owner_id", nil).Error
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"errors"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrPlanMismatch is returned when a plan is named that does not belong to the software
+var ErrPlanMismatch = errors.New("plan does not belong to this software")
+
+// ValidatePlan checks that planID, when set, is one of the software's plans
+func ValidatePlan(softwareID uint, planID *uint) error {
+	return validatePlan(config.DB, softwareID, planID)
+}
+
+func validatePlan(db *gorm.DB, softwareID uint, planID *uint) error {
+	if planID == nil {
+		return nil
+	}
+	var plan models.SoftwarePlan
+	if err := db.First(&plan, *planID).Error; err != nil || plan.SoftwareID != softwareID {
+		return ErrPlanMismatch
+	}
+	return nil
+}
+
+// planTiers loads the tier of every plan, keyed by plan ID
+func planTiers() (map[uint]int, error) {
+	var plans []models.SoftwarePlan
+	if err := config.DB.Select("id", "tier").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	tiers := make(map[uint]int, len(plans))
+	for _, plan := range plans {
+		tiers[plan.ID] = plan.Tier
+	}
+	return tiers, nil
+}
+
+// higherPlan returns whichever plan has the higher tier. Naming a plan beats naming none.
+func higherPlan(a, b *uint, tiers map[uint]int) *uint {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if tiers[*b] > tiers[*a] {
+		return b
+	}
+	return a
+}
+
+// upgradePlan moves an auto-assigned row up to planID when that plan is higher than its current one.
+// Manual assignments keep whatever plan an admin chose.
+func upgradePlan(assignment models.AssignedSoftware, planID *uint, tiers map[uint]int) error {
+	best := planUpgrade(assignment, planID, tiers)
+	if best == nil {
+		return nil
+	}
+	return config.DB.Model(&assignment).Update("plan_id", *best).Error
+}
+
+// planUpgrade returns the plan upgradePlan would move an assignment to, or nil when it stays as it is
+func planUpgrade(assignment models.AssignedSoftware, planID *uint, tiers map[uint]int) *uint {
+	if !isAutoAssigned(assignment.Source) || planID == nil {
+		return nil
+	}
+	best := higherPlan(assignment.PlanID, planID, tiers)
+	if assignment.PlanID != nil && *best == *assignment.PlanID {
+		return nil
+	}
+	return best
+}
+
+// priceFor returns the seat price, billing period and currency of a plan, or of the software when no plan applies
+func priceFor(software models.Software, plan *models.SoftwarePlan) (float64, string, string) {
+	if plan != nil {
+		return plan.SeatPrice, plan.BillingPeriod, plan.Currency
+	}
+	return software.SeatPrice, software.BillingPeriod, software.Currency
+}
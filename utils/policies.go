@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// Constants for software policy kinds
+const (
+	PolicyConflict = "conflict"
+	PolicyRequires = "requires"
+)
+
+// ErrPolicyViolation is returned when an assignment would break a conflict or requires policy
+var ErrPolicyViolation = errors.New("software policy violation")
+
+// NormalizePolicyKind maps a policy kind in any letter case onto its canonical spelling
+func NormalizePolicyKind(kind string) (string, bool) {
+	for _, known := range []string{PolicyConflict, PolicyRequires} {
+		if strings.EqualFold(kind, known) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// checkPolicies returns an ErrPolicyViolation describing the broken policy when giving the software
+// to the staff member would hold it together with a conflicting software, or without a software it
+// requires. Pending assignments count as held.
+func checkPolicies(db *gorm.DB, staffID, softwareID uint) error {
+	var policies []models.SoftwarePolicy
+	if err := db.Where("software_id = ? OR (kind = ? AND related_software_id = ?)", softwareID, PolicyConflict, softwareID).
+		Find(&policies).Error; err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	var heldIDs []uint
+	if err := db.Model(&models.AssignedSoftware{}).Where("staff_id = ?", staffID).
+		Pluck("software_id", &heldIDs).Error; err != nil {
+		return err
+	}
+	held := make(map[uint]bool, len(heldIDs))
+	for _, id := range heldIDs {
+		held[id] = true
+	}
+
+	for _, policy := range policies {
+		other := policy.RelatedSoftwareID
+		if other == softwareID {
+			other = policy.SoftwareID
+		}
+		if policy.Kind == PolicyConflict && held[other] {
+			return policyViolation(db, policy.Kind, softwareID, other, "which the staff member already holds")
+		}
+		if policy.Kind == PolicyRequires && !held[other] {
+			return policyViolation(db, policy.Kind, softwareID, other, "which the staff member does not hold")
+		}
+	}
+	return nil
+}
+
+// policyViolation wraps ErrPolicyViolation with a message naming both software
+func policyViolation(db *gorm.DB, kind string, softwareID, otherID uint, detail string) error {
+	names := softwareNames(db, []uint{softwareID, otherID})
+	return fmt.Errorf("%w: %s, %s", ErrPolicyViolation, policyMessage(kind, names[softwareID], names[otherID]), detail)
+}
+
+// policyMessage states a policy between two software in words
+func policyMessage(kind, software, related string) string {
+	if kind == PolicyConflict {
+		return software + " conflicts with " + related
+	}
+	return software + " requires " + related
+}
+
+// softwareNames maps software IDs to their names
+func softwareNames(db *gorm.DB, ids []uint) map[uint]string {
+	var software []models.Software
+	db.Select("id", "name").Where("id IN ?", ids).Find(&software)
+	names := make(map[uint]string, len(software))
+	for _, sw := range software {
+		names[sw.ID] = sw.Name
+	}
+	return names
+}
+
+// OrderByPrerequisites orders software so that each comes after the software it requires, when both
+// are in the list, and otherwise keeps the given order. Cyclic requirements are left as they are.
+func OrderByPrerequisites(softwareIDs []uint, policies []models.SoftwarePolicy) []uint {
+	requires := make(map[uint][]uint)
+	for _, policy := range policies {
+		if policy.Kind == PolicyRequires {
+			requires[policy.SoftwareID] = append(requires[policy.SoftwareID], policy.RelatedSoftwareID)
+		}
+	}
+	listed := make(map[uint]bool, len(softwareIDs))
+	for _, id := range softwareIDs {
+		listed[id] = true
+	}
+
+	ordered := make([]uint, 0, len(softwareIDs))
+	visited := make(map[uint]bool, len(softwareIDs))
+	var visit func(id uint)
+	visit = func(id uint) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		for _, required := range requires[id] {
+			if listed[required] {
+				visit(required)
+			}
+		}
+		ordered = append(ordered, id)
+	}
+	for _, id := range softwareIDs {
+		visit(id)
+	}
+	return ordered
+}
+
+// prerequisiteRank gives the position of each software once ordered by OrderByPrerequisites, so that
+// assigning in that order never trips a requires policy whose prerequisite is assigned alongside
+func prerequisiteRank(softwareIDs []uint) map[uint]int {
+	var policies []models.SoftwarePolicy
+	if err := config.DB.Where("kind = ?", PolicyRequires).Find(&policies).Error; err != nil || len(policies) == 0 {
+		return nil
+	}
+	rank := make(map[uint]int, len(softwareIDs))
+	for i, id := range OrderByPrerequisites(softwareIDs, policies) {
+		rank[id] = i
+	}
+	return rank
+}
+
+// orderEntitlements sorts entitlements so that prerequisites are assigned first
+func orderEntitlements(entitlements []entitlement) {
+	ids := make([]uint, len(entitlements))
+	for i, e := range entitlements {
+		ids[i] = e.SoftwareID
+	}
+	rank := prerequisiteRank(ids)
+	sort.SliceStable(entitlements, func(i, j int) bool {
+		return rank[entitlements[i].SoftwareID] < rank[entitlements[j].SoftwareID]
+	})
+}
+
+// FindPolicyViolations lists the assignments in assigned_software that break a conflict or requires
+// policy, e.g. ones made before the policy was declared. A conflict is reported once per staff member.
+func FindPolicyViolations() ([]models.PolicyViolation, error) {
+	var policies []models.SoftwarePolicy
+	if err := config.DB.Order("id").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, 2*len(policies))
+	for _, policy := range policies {
+		ids = append(ids, policy.SoftwareID, policy.RelatedSoftwareID)
+	}
+	names := softwareNames(config.DB, ids)
+
+	violations := []models.PolicyViolation{}
+	for _, policy := range policies {
+		var holders []struct {
+			StaffID    uint
+			StaffEmail string
+		}
+		query := config.DB.Table("assigned_software AS a").
+			Select("a.staff_id, staff.email AS staff_email").
+			Joins("JOIN staff ON staff.id = a.staff_id").
+			Where("a.software_id = ?", policy.SoftwareID)
+		if policy.Kind == PolicyConflict {
+			query = query.Where("EXISTS (SELECT 1 FROM assigned_software b WHERE b.staff_id = a.staff_id AND b.software_id = ?)", policy.RelatedSoftwareID)
+		} else {
+			query = query.Where("NOT EXISTS (SELECT 1 FROM assigned_software b WHERE b.staff_id = a.staff_id AND b.software_id = ?)", policy.RelatedSoftwareID)
+		}
+		if err := query.Order("a.staff_id").Scan(&holders).Error; err != nil {
+			return nil, err
+		}
+
+		for _, holder := range holders {
+			violations = append(violations, models.PolicyViolation{
+				PolicyID:          policy.ID,
+				Kind:              policy.Kind,
+				StaffID:           holder.StaffID,
+				StaffEmail:        holder.StaffEmail,
+				SoftwareID:        policy.SoftwareID,
+				Software:          names[policy.SoftwareID],
+				RelatedSoftwareID: policy.RelatedSoftwareID,
+				RelatedSoftware:   names[policy.RelatedSoftwareID],
+				Message:           policyMessage(policy.Kind, names[policy.SoftwareID], names[policy.RelatedSoftwareID]),
+			})
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"software_management/config"
+	"software_management/models"
+)
+
+// Actions of a previewed assignment change
+const (
+	PreviewAssign  = "assign"
+	PreviewUpgrade = "upgrade"
+	PreviewRevoke  = "revoke"
+	PreviewSkip    = "skip"
+)
+
+// PreviewAutoAssign works out what AutoAssignSoftwareToStaffByUnit would do for a list of staff,
+// including assignments it would skip for lack of seats or license keys or because they break a
+// software policy, without writing anything.
+func PreviewAutoAssign(softwareID uint, planID *uint, staffList []models.Staff, source string) (models.AssignmentPreview, error) {
+	preview, err := newAssignmentPreview(softwareID, source)
+	if err != nil {
+		return preview, err
+	}
+	tiers, err := planTiers()
+	if err != nil {
+		return preview, err
+	}
+	usage, err := GetSeatUsage(softwareID)
+	if err != nil {
+		return preview, err
+	}
+	excluded, err := activeExclusions(softwareID)
+	if err != nil {
+		return preview, err
+	}
+
+	var totalKeys, freeKeys int64
+	config.DB.Model(&models.LicenseKey{}).Where("software_id = ?", softwareID).Count(&totalKeys)
+	config.DB.Model(&models.LicenseKey{}).Where("software_id = ? AND assigned_software_id IS NULL", softwareID).Count(&freeKeys)
+	freeSeats := usage.Free
+
+	for _, staff := range staffList {
+		change := models.AssignmentChange{StaffID: staff.ID, StaffEmail: staff.Email, SoftwareID: softwareID, PlanID: planID}
+
+		var existing models.AssignedSoftware
+		if excluded[staff.ID][softwareID] {
+			change.Action = PreviewSkip
+			change.Reason = ErrStaffExcluded.Error()
+			preview.Skip++
+		} else if err := config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, softwareID).First(&existing).Error; err == nil {
+			upgrade := planUpgrade(existing, planID, tiers)
+			if upgrade == nil {
+				preview.Unchanged++
+				continue
+			}
+			change.Action = PreviewUpgrade
+			change.PlanID = upgrade
+			preview.Upgrade++
+		} else if err := checkPolicies(config.DB, staff.ID, softwareID); err != nil {
+			change.Action = PreviewSkip
+			change.Reason = err.Error()
+			preview.Skip++
+		} else if !usage.Unlimited && freeSeats == 0 {
+			change.Action = PreviewSkip
+			change.Reason = ErrNoSeatsAvailable.Error()
+			preview.Skip++
+		} else if totalKeys > 0 && freeKeys == 0 {
+			change.Action = PreviewSkip
+			change.Reason = ErrNoLicenseKeyAvailable.Error()
+			preview.Skip++
+		} else {
+			change.Action = PreviewAssign
+			preview.Assign++
+			freeSeats--
+			freeKeys--
+		}
+		preview.Changes = append(preview.Changes, change)
+	}
+	return preview, nil
+}
+
+// PreviewAutoRevoke works out what AutoRevokeSoftwareFromStaff would do for a list of staff, without writing anything.
+// Staff who keep another grant for the software are counted as unchanged.
+func PreviewAutoRevoke(softwareID uint, staffList []models.Staff, sourceType string, sourceID uint) (models.AssignmentPreview, error) {
+	preview, err := newAssignmentPreview(softwareID, sourceType)
+	if err != nil {
+		return preview, err
+	}
+
+	key := grantKey{SourceType: sourceType, SourceID: sourceID}
+	for _, staff := range staffList {
+		var existing models.AssignedSoftware
+		if err := config.DB.Where("staff_id = ? AND software_id = ?", staff.ID, softwareID).
+			First(&existing).Error; err != nil {
+			preview.Unchanged++
+			continue
+		}
+		grants, err := loadGrants(config.DB, existing)
+		if err != nil {
+			return preview, err
+		}
+		granted, kept := false, false
+		for _, grant := range grants {
+			if grantMatches(grant, key) {
+				granted = true
+			} else {
+				kept = true
+			}
+		}
+		if !granted || kept {
+			preview.Unchanged++
+			continue
+		}
+		preview.Changes = append(preview.Changes, models.AssignmentChange{
+			StaffID:    staff.ID,
+			StaffEmail: staff.Email,
+			SoftwareID: softwareID,
+			Action:     PreviewRevoke,
+			PlanID:     existing.PlanID,
+		})
+		preview.Revoke++
+	}
+	return preview, nil
+}
+
+func newAssignmentPreview(softwareID uint, source string) (models.AssignmentPreview, error) {
+	preview := models.AssignmentPreview{
+		DryRun:     true,
+		SoftwareID: softwareID,
+		Source:     source,
+		Changes:    []models.AssignmentChange{},
+	}
+	var software models.Software
+	if err := config.DB.Select("id", "name").First(&software, softwareID).Error; err != nil {
+		return preview, err
+	}
+	preview.Software = software.Name
+	return preview, nil
+}
@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultIdleDays is the idle threshold used for software without a reclamation policy
+const DefaultIdleDays = 60
+
+// Stages a reclamation policy can put an idle seat in
+const (
+	ReclaimNone   = ""
+	ReclaimWarn   = "warn"
+	ReclaimRevoke = "revoke"
+)
+
+// IdleSince is when a seat was last used, or when it was assigned if no usage was ever recorded
+func IdleSince(assignment models.AssignedSoftware) time.Time {
+	if assignment.LastUsedAt != nil {
+		return *assignment.LastUsedAt
+	}
+	return assignment.AssignedAt
+}
+
+// ReclamationStage decides what a policy does with a seat idle since idleSince.
+// The holder is warned WarningDays before the seat reaches IdleDays, and the seat is only
+// revoked once that warning is at least WarningDays old.
+func ReclamationStage(policy models.ReclamationPolicy, idleSince time.Time, warnedAt *time.Time, now time.Time) string {
+	if !policy.Enabled {
+		return ReclaimNone
+	}
+	idleFor := now.Sub(idleSince)
+	if idleFor < time.Duration(policy.IdleDays-policy.WarningDays)*oneDay {
+		return ReclaimNone
+	}
+	if warnedAt == nil {
+		return ReclaimWarn
+	}
+	if idleFor >= time.Duration(policy.IdleDays)*oneDay && now.Sub(*warnedAt) >= time.Duration(policy.WarningDays)*oneDay {
+		return ReclaimRevoke
+	}
+	return ReclaimNone
+}
+
+// ReclaimDate is the earliest time a policy will revoke a seat idle since idleSince
+func ReclaimDate(policy models.ReclamationPolicy, idleSince time.Time, warnedAt *time.Time, now time.Time) time.Time {
+	date := idleSince.Add(time.Duration(policy.IdleDays) * oneDay)
+	earliest := now
+	if warnedAt != nil {
+		earliest = *warnedAt
+	}
+	if notice := earliest.Add(time.Duration(policy.WarningDays) * oneDay); notice.After(date) {
+		date = notice
+	}
+	return date
+}
+
+// ProcessSeatReclamation applies every enabled reclamation policy: holders of seats nearing the
+// idle threshold are warned, and seats whose warning period has passed are revoked. Staff whose
+// reclaimed seat a match or rule granted are excluded from the software, so it does not come back.
+func ProcessSeatReclamation() error {
+	var policies []models.ReclamationPolicy
+	if err := config.DB.Preload("Software").Where("enabled = ?", true).Find(&policies).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, policy := range policies {
+		warnFrom := now.Add(-time.Duration(policy.IdleDays-policy.WarningDays) * oneDay)
+
+		var assignments []models.AssignedSoftware
+		if err := config.DB.
+			Where("software_id = ? AND status <> ? AND COALESCE(last_used_at, assigned_at) <= ?",
+				policy.SoftwareID, AssignmentPending, warnFrom).
+			Find(&assignments).Error; err != nil {
+			return err
+		}
+
+		for _, assignment := range assignments {
+			since := IdleSince(assignment)
+			warnedAt := idleSeatWarnedAt(assignment, since)
+
+			switch ReclamationStage(policy, since, warnedAt, now) {
+			case ReclaimWarn:
+				warnIdleSeat(policy, assignment, since, now)
+			case ReclaimRevoke:
+				idleDays := int(now.Sub(since) / oneDay)
+				if err := reclaimSeat(assignment, idleDays); err != nil {
+					log.Printf("Failed to reclaim assignment %d: %v", assignment.ID, err)
+					continue
+				}
+				log.Printf("♻️ Reclaimed %s seat from staff %d after %d idle days",
+					policy.Software.Name, assignment.StaffID, idleDays)
+			}
+		}
+	}
+	return nil
+}
+
+// reclaimSeat revokes an idle assignment and, in the same transaction, excludes its holder from the
+// software when a match or rule granted it
+func reclaimSeat(assignment models.AssignedSoftware, idleDays int) error {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		grants, err := loadGrants(tx, assignment)
+		if err != nil {
+			return err
+		}
+		if err := revokeAssignment(tx, assignment); err != nil {
+			return err
+		}
+		_, err = excludeAutoGranted(tx, assignment, grants, fmt.Sprintf("Reclaimed: idle %d days", idleDays))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	logRevoked(assignment, ActionReclaimed)
+	return nil
+}
+
+// idleSeatKey identifies the warning for one idle stretch of an assignment, so renewed
+// usage followed by another idle stretch gets a fresh warning
+func idleSeatKey(assignment models.AssignedSoftware, since time.Time) string {
+	return fmt.Sprintf("%s:%d:%d", ReminderIdleSeat, assignment.ID, since.Unix())
+}
+
+// idleSeatWarnedAt returns when the holder was warned about the current idle stretch, if at all
+func idleSeatWarnedAt(assignment models.AssignedSoftware, since time.Time) *time.Time {
+	var warning models.Reminder
+	if err := config.DB.Where("dedup_key = ?", idleSeatKey(assignment, since)).First(&warning).Error; err != nil {
+		return nil
+	}
+	return &warning.FiredAt
+}
+
+func warnIdleSeat(policy models.ReclamationPolicy, assignment models.AssignedSoftware, since, now time.Time) {
+	var staff models.StaffPlain
+	config.DB.Select("id", "email").First(&staff, assignment.StaffID)
+
+	reclaimOn := ReclaimDate(policy, since, nil, now)
+	fireReminder(models.Reminder{
+		Kind:     ReminderIdleSeat,
+		DedupKey: idleSeatKey(assignment, since),
+		DueDate:  reclaimOn,
+		Message: fmt.Sprintf("%s seat of %s has been idle for %d days and will be reclaimed on %s",
+			policy.Software.Name, staff.Email, int(now.Sub(since)/oneDay), reclaimOn.Format("2006-01-02")),
+	})
+}
+
+// FindIdleSeats lists seats idle for at least minDays, with what they cost. When minDays is 0 each
+// software's own policy threshold is used, falling back to DefaultIdleDays.
+func FindIdleSeats(softwareID uint, minDays int) (models.IdleSeatReport, error) {
+	report := models.IdleSeatReport{
+		Seats:       []models.IdleSeat{},
+		MonthlyCost: map[string]float64{},
+		AnnualCost:  map[string]float64{},
+	}
+
+	var policies []models.ReclamationPolicy
+	if err := config.DB.Find(&policies).Error; err != nil {
+		return report, err
+	}
+	policyBySoftware := make(map[uint]models.ReclamationPolicy)
+	for _, p := range policies {
+		policyBySoftware[p.SoftwareID] = p
+	}
+	threshold := func(softwareID uint) int {
+		if minDays > 0 {
+			return minDays
+		}
+		if p, ok := policyBySoftware[softwareID]; ok {
+			return p.IdleDays
+		}
+		return DefaultIdleDays
+	}
+
+	now := time.Now()
+	query := config.DB.Model(&models.AssignedSoftware{}).Where("status <> ?", AssignmentPending)
+	if softwareID != 0 {
+		query = query.Where("software_id = ?", softwareID)
+	}
+	if minDays > 0 {
+		query = query.Where("COALESCE(last_used_at, assigned_at) <= ?", now.Add(-time.Duration(minDays)*oneDay))
+	}
+	var assignments []models.AssignedSoftware
+	if err := query.Find(&assignments).Error; err != nil {
+		return report, err
+	}
+
+	var software []models.Software
+	if err := config.DB.Find(&software).Error; err != nil {
+		return report, err
+	}
+	softwareByID := make(map[uint]models.Software)
+	for _, sw := range software {
+		softwareByID[sw.ID] = sw
+	}
+	var plans []models.SoftwarePlan
+	if err := config.DB.Find(&plans).Error; err != nil {
+		return report, err
+	}
+	plansByID := make(map[uint]models.SoftwarePlan)
+	for _, p := range plans {
+		plansByID[p.ID] = p
+	}
+	var staff []models.StaffPlain
+	if err := config.DB.Select("id", "email").Find(&staff).Error; err != nil {
+		return report, err
+	}
+	emails := make(map[uint]string)
+	for _, s := range staff {
+		emails[s.ID] = s.Email
+	}
+
+	for _, assignment := range assignments {
+		since := IdleSince(assignment)
+		idleDays := int(now.Sub(since) / oneDay)
+		if idleDays < threshold(assignment.SoftwareID) {
+			continue
+		}
+
+		sw := softwareByID[assignment.SoftwareID]
+		var plan *models.SoftwarePlan
+		if assignment.PlanID != nil {
+			if p, ok := plansByID[*assignment.PlanID]; ok {
+				plan = &p
+			}
+		}
+		price, billingPeriod, currency := priceFor(sw, plan)
+		monthly := roundMoney(MonthlySeatCost(price, billingPeriod))
+
+		seat := models.IdleSeat{
+			AssignmentID: assignment.ID,
+			StaffID:      assignment.StaffID,
+			StaffEmail:   emails[assignment.StaffID],
+			SoftwareID:   assignment.SoftwareID,
+			Software:     sw.Name,
+			Source:       assignment.Source,
+			AssignedAt:   assignment.AssignedAt,
+			LastUsedAt:   assignment.LastUsedAt,
+			IdleDays:     idleDays,
+			Currency:     currency,
+			MonthlyCost:  monthly,
+		}
+		if policy, ok := policyBySoftware[assignment.SoftwareID]; ok && policy.Enabled {
+			reclaimOn := ReclaimDate(policy, since, idleSeatWarnedAt(assignment, since), now)
+			seat.ReclaimAfter = &reclaimOn
+		}
+
+		report.Seats = append(report.Seats, seat)
+		report.MonthlyCost[currency] = roundMoney(report.MonthlyCost[currency] + monthly)
+		report.AnnualCost[currency] = roundMoney(report.AnnualCost[currency] + monthly*12)
+	}
+
+	sort.Slice(report.Seats, func(i, j int) bool {
+		return report.Seats[i].IdleDays > report.Seats[j].IdleDays
+	})
+	return report, nil
+}
@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// Kinds of assignment drift
+const (
+	DriftMissing       = "missing"
+	DriftStale         = "stale"
+	DriftInactiveStaff = "inactive_staff"
+	DriftMissingGrant  = "missing_grant"
+	DriftStaleGrant    = "stale_grant"
+)
+
+// Reconcile works out each staff member's desired software from all matches and rules and compares it
+// with assigned_software. It reports auto-assignments that are missing, auto-assignments no match or
+// rule grants any more, and assignments still held by inactive staff other than those an offboarding
+// kept, as well as grants that are not
+// recorded on an assignment or are recorded but no longer given. With apply set, every drift item
+// is corrected through the usual create and revoke paths, which write the assignment logs.
+// staffID limits the run to one staff member when non-zero.
+func Reconcile(staffID uint, apply bool) (models.DriftReport, error) {
+	report := models.DriftReport{GeneratedAt: time.Now(), Applied: apply, Items: []models.DriftItem{}}
+
+	sources, err := loadGrantSources()
+	if err != nil {
+		return report, err
+	}
+	kept, err := keptAssignments()
+	if err != nil {
+		return report, err
+	}
+
+	var staffList []models.Staff
+	query := config.DB.Preload("Department").Preload("Team")
+	if staffID != 0 {
+		query = query.Where("id = ?", staffID)
+	}
+	if err := query.Find(&staffList).Error; err != nil {
+		return report, err
+	}
+
+	var software []models.Software
+	if err := config.DB.Select("id", "name").Find(&software).Error; err != nil {
+		return report, err
+	}
+	softwareNames := make(map[uint]string)
+	for _, sw := range software {
+		softwareNames[sw.ID] = sw.Name
+	}
+
+	for _, staff := range staffList {
+		report.StaffChecked++
+
+		var assignments []models.AssignedSoftware
+		if err := config.DB.Where("staff_id = ?", staff.ID).Find(&assignments).Error; err != nil {
+			return report, err
+		}
+		held := make(map[uint]models.AssignedSoftware)
+		for _, assignment := range assignments {
+			held[assignment.SoftwareID] = assignment
+		}
+
+		item := func(kind string, softwareID uint, source string) models.DriftItem {
+			return models.DriftItem{
+				Kind:       kind,
+				StaffID:    staff.ID,
+				StaffEmail: staff.Email,
+				SoftwareID: softwareID,
+				Software:   softwareNames[softwareID],
+				Source:     source,
+			}
+		}
+
+		// Inactive staff should hold nothing but what their offboarding kept
+		if strings.EqualFold(staff.Status, "inactive") {
+			for _, assignment := range assignments {
+				if kept[assignment.ID] {
+					continue
+				}
+				assignmentID := assignment.ID
+				drift := item(DriftInactiveStaff, assignment.SoftwareID, assignment.Source)
+				drift.AssignmentID = &assignmentID
+				if apply {
+					fixDrift(&drift, RevokeAssignment(assignment, ActionUnassigned))
+				}
+				report.InactiveStaff++
+				report.Items = append(report.Items, drift)
+			}
+			continue
+		}
+
+		entitlements := sources.resolve(staff.ID, staff.DepartmentID, staff.TeamID, StaffAttributes(staff))
+		granted := entitledGrants(entitlements)
+		for _, e := range entitlements {
+			if assignment, ok := held[e.SoftwareID]; ok {
+				grants, err := loadGrants(config.DB, assignment)
+				if err != nil {
+					return report, err
+				}
+				for _, grant := range e.Grants {
+					if grantStillEntitled(grant, grants) {
+						continue
+					}
+					assignmentID := assignment.ID
+					drift := item(DriftMissingGrant, e.SoftwareID, grant.SourceType)
+					drift.SourceID = grant.SourceID
+					drift.AssignmentID = &assignmentID
+					if apply {
+						fixDrift(&drift, AddGrant(assignment.ID, grant))
+					}
+					report.MissingGrants++
+					report.Items = append(report.Items, drift)
+				}
+				continue
+			}
+
+			drift := item(DriftMissing, e.SoftwareID, e.Source)
+			drift.PlanID = e.PlanID
+			if apply {
+				record := models.AssignedSoftware{
+					StaffID:    staff.ID,
+					SoftwareID: e.SoftwareID,
+					PlanID:     e.PlanID,
+					Source:     e.Source,
+					AssignedAt: time.Now(),
+					StartsAt:   futureStart(staff.StartDate, time.Now()),
+					Grants:     e.Grants,
+				}
+				err := CreateAssignment(&record)
+				if err == nil {
+					logAssigned(record)
+				}
+				fixDrift(&drift, err)
+			}
+			report.Missing++
+			report.Items = append(report.Items, drift)
+		}
+
+		for _, assignment := range assignments {
+			grants, err := loadGrants(config.DB, assignment)
+			if err != nil {
+				return report, err
+			}
+			current := granted[assignment.SoftwareID]
+			stale := func(grant models.AssignmentGrant) bool {
+				return isAutoGrant(grant) && !grantStillEntitled(grant, current)
+			}
+
+			var staleGrants []models.AssignmentGrant
+			for _, grant := range grants {
+				if stale(grant) {
+					staleGrants = append(staleGrants, grant)
+				}
+			}
+			if len(staleGrants) == 0 {
+				continue
+			}
+
+			assignmentID := assignment.ID
+			if len(staleGrants) == len(grants) {
+				drift := item(DriftStale, assignment.SoftwareID, assignment.Source)
+				drift.AssignmentID = &assignmentID
+				if apply {
+					fixDrift(&drift, RevokeAssignment(assignment, ActionUnassigned))
+				}
+				report.Stale++
+				report.Items = append(report.Items, drift)
+				continue
+			}
+
+			var removeErr error
+			if apply {
+				removeErr = removeGrants(assignment, stale, ActionUnassigned)
+			}
+			for _, grant := range staleGrants {
+				drift := item(DriftStaleGrant, assignment.SoftwareID, grant.SourceType)
+				drift.SourceID = grant.SourceID
+				drift.AssignmentID = &assignmentID
+				if apply {
+					fixDrift(&drift, removeErr)
+				}
+				report.StaleGrants++
+				report.Items = append(report.Items, drift)
+			}
+		}
+	}
+
+	for _, drift := range report.Items {
+		if drift.Fixed {
+			report.Fixed++
+		}
+	}
+	return report, nil
+}
+
+// fixDrift records the outcome of correcting a drift item
+func fixDrift(drift *models.DriftItem, err error) {
+	if err != nil {
+		drift.Error = err.Error()
+		return
+	}
+	drift.Fixed = true
+}
+
+// ReconcileAssignments is the scheduled drift check. It logs the drift it finds and fixes it only
+// when RECONCILE_AUTO_APPLY is true.
+func ReconcileAssignments() error {
+	apply, _ := strconv.ParseBool(os.Getenv("RECONCILE_AUTO_APPLY"))
+	report, err := Reconcile(0, apply)
+	if err != nil {
+		return err
+	}
+	if len(report.Items) > 0 {
+		log.Printf("Assignment drift: %d missing, %d stale, %d held by inactive staff, %d missing and %d stale grants, %d fixed",
+			report.Missing, report.Stale, report.InactiveStaff, report.MissingGrants, report.StaleGrants, report.Fixed)
+	}
+	return nil
+}
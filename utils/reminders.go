@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// Reminder kinds
+const (
+	ReminderContractNotice   = "contract_notice_deadline"
+	ReminderContractEnd      = "contract_end"
+	ReminderIdleSeat         = "idle_seat_warning"
+	ReminderBudgetOverspend  = "budget_overspend"
+	ReminderAssignmentExpiry = "assignment_expiry"
+)
+
+// ProcessContractReminders fires a reminder for every contract whose notice deadline or
+// end date falls within REMINDER_LEAD_DAYS (default 30). A notice deadline that passed before the
+// job saw it, e.g. after downtime, fires as overdue. Reminders already fired are skipped.
+func ProcessContractReminders() error {
+	lead := time.Duration(envDays("REMINDER_LEAD_DAYS", 30)) * 24 * time.Hour
+
+	renewals, err := FindUpcomingRenewals(lead)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cutoff := now.Add(lead)
+	for _, renewal := range renewals {
+		contract := renewal.Contract
+
+		if renewal.NoticeDeadline.Before(now) {
+			msg := fmt.Sprintf("Notice deadline for contract %s (%s) passed %d days ago; auto-renew: %t",
+				contract.ContractNumber, contract.Software.Name, -renewal.DaysUntilNotice, contract.AutoRenew)
+			fireContractReminder(contract, ReminderContractNotice, renewal.NoticeDeadline, msg)
+		} else if !renewal.NoticeDeadline.After(cutoff) {
+			msg := fmt.Sprintf("Notice deadline for contract %s (%s) is in %d days; auto-renew: %t",
+				contract.ContractNumber, contract.Software.Name, renewal.DaysUntilNotice, contract.AutoRenew)
+			fireContractReminder(contract, ReminderContractNotice, renewal.NoticeDeadline, msg)
+		}
+
+		if !contract.EndDate.After(cutoff) {
+			msg := fmt.Sprintf("Contract %s (%s) ends in %d days; auto-renew: %t",
+				contract.ContractNumber, contract.Software.Name, renewal.DaysUntilEnd, contract.AutoRenew)
+			fireContractReminder(contract, ReminderContractEnd, contract.EndDate, msg)
+		}
+	}
+	return nil
+}
+
+// fireContractReminder stores and emits a contract reminder unless it has already fired
+func fireContractReminder(contract models.Contract, kind string, due time.Time, message string) {
+	contractID := contract.ID
+	fireReminder(models.Reminder{
+		Kind:       kind,
+		DedupKey:   fmt.Sprintf("%s:%d:%s", kind, contract.ID, due.Format("2006-01-02")),
+		ContractID: &contractID,
+		DueDate:    due,
+		Message:    message,
+	})
+}
+
+// fireReminder stores a reminder keyed by its DedupKey and emits it the first time only
+func fireReminder(reminder models.Reminder) {
+	var existing int64
+	if err := config.DB.Model(&models.Reminder{}).Where("dedup_key = ?", reminder.DedupKey).Count(&existing).Error; err != nil {
+		log.Println("Failed to check reminder:", err)
+		return
+	}
+	if existing > 0 {
+		return
+	}
+
+	reminder.FiredAt = time.Now()
+	if err := config.DB.Create(&reminder).Error; err != nil {
+		log.Println("Failed to store reminder:", err)
+		return
+	}
+	log.Printf("🔔 Reminder [%s]: %s", reminder.Kind, reminder.Message)
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RunEvery runs job in a background goroutine straight away and then once per interval.
+// Errors are logged and never stop the loop.
+func RunEvery(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(); err != nil {
+				log.Printf("Scheduled job %s failed: %v", name, err)
+			}
+			<-ticker.C
+		}
+	}()
+	log.Printf("Scheduled job %s every %s", name, interval)
+}
+
+// SchedulerInterval reads SCHEDULER_INTERVAL (e.g. "1h", "15m"), defaulting to one hour
+func SchedulerInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("SCHEDULER_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// envDays reads a whole number of days from the environment, falling back to def
+func envDays(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n >= 0 {
+		return n
+	}
+	return def
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+
+	"software_management/config"
+	"software_management/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoSeatsAvailable is returned when every purchased seat of a software is already assigned
+var ErrNoSeatsAvailable = errors.New("no seats available: the license pool for this software is full")
+
+// GetSeatUsage returns the purchased, used and free seats for a software
+func GetSeatUsage(softwareID uint) (models.SeatUsage, error) {
+	return seatUsage(config.DB, softwareID)
+}
+
+// seatUsage counts used seats against the software's seat limit using the given DB handle. Pending
+// assignments take no seat until they activate.
+func seatUsage(db *gorm.DB, softwareID uint) (models.SeatUsage, error) {
+	usage := models.SeatUsage{SoftwareID: softwareID}
+
+	var software models.Software
+	if err := db.First(&software, softwareID).Error; err != nil {
+		return usage, err
+	}
+
+	var used int64
+	if err := db.Model(&models.AssignedSoftware{}).
+		Where("software_id = ? AND status <> ?", softwareID, AssignmentPending).
+		Count(&used).Error; err != nil {
+		return usage, err
+	}
+
+	usage.Purchased = software.SeatLimit
+	usage.Used = int(used)
+	usage.Unlimited = software.SeatLimit <= 0
+	if !usage.Unlimited && usage.Purchased > usage.Used {
+		usage.Free = usage.Purchased - usage.Used
+	}
+	return usage, nil
+}
+
+// claimSeat checks that a software has a free seat for one more active assignment. It locks the
+// software row first, so that concurrent assignments of the same software wait for each other
+// instead of both taking the last seat.
+func claimSeat(tx *gorm.DB, softwareID uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		First(&models.Software{}, softwareID).Error; err != nil {
+		return err
+	}
+	usage, err := seatUsage(tx, softwareID)
+	if err != nil {
+		return err
+	}
+	if !usage.Unlimited && usage.Free == 0 {
+		return ErrNoSeatsAvailable
+	}
+	return nil
+}
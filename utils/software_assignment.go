@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"log"
 	"time"
 
 	"software_management/config"
 	"software_management/models"
+
+	"gorm.io/gorm"
 )
 
 // Constants for assignment sources
@@ -12,78 +15,188 @@ const (
 	SourceDepartment   = "department"
 	SourceTeam         = "team"
 	SourceOrganization = "organization"
+	SourceRule         = "rule"
 )
 
-// AutoAssignSoftwareToStaff assigns software based on department, team, and org matches.
-func AutoAssignSoftwareToStaff(staffID, departmentID, teamID uint) error {
-	now := time.Now()
+// Constants for assignment statuses
+const (
+	AssignmentPending = "pending"
+	AssignmentActive  = "active"
+)
+
+// Constants for assignment log actions
+const (
+	ActionAssigned   = "Assigned"
+	ActionUnassigned = "Unassigned"
+	ActionReclaimed  = "Reclaimed"
+	ActionRuleDelete = "Unassigned (Rule Deleted)"
+	ActionExpired    = "Expired"
+	ActionReviewed   = "Unassigned (Access Review)"
+	ActionOffboarded = "Unassigned (Offboarding)"
+)
 
-	// Fetch already assigned software IDs
-	var existingAssignments []uint
-	if err := config.DB.Model(&models.AssignedSoftware{}).
-		Where("staff_id = ?", staffID).
-		Pluck("software_id", &existingAssignments).Error; err != nil {
+// CreateAssignment checks and inserts an assignment in its own transaction. Every code path that
+// creates assigned_software rows should go through here.
+func CreateAssignment(record *models.AssignedSoftware) error {
+	var overBudget *models.Department
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		overBudget, err = createAssignment(tx, record)
 		return err
+	})
+	if err == nil && overBudget != nil {
+		alertOverBudget(*overBudget, *record)
+	}
+	return err
+}
+
+// createAssignment does the checks and writes of CreateAssignment inside the caller's transaction:
+// plan, software policies, free seats and department budget, then the insert and a license key for
+// key-based software. It returns the department the assignment took over budget, if any, for the
+// caller to alert once committed.
+func createAssignment(tx *gorm.DB, record *models.AssignedSoftware) (*models.Department, error) {
+	// A record without grants is granted by its source alone
+	if len(record.Grants) == 0 {
+		record.Grants = []models.AssignmentGrant{implicitGrant(*record)}
 	}
-	existing := make(map[uint]bool)
-	for _, sid := range existingAssignments {
-		existing[sid] = true
+	if err := validatePlan(tx, record.SoftwareID, record.PlanID); err != nil {
+		return nil, err
 	}
+	if err := checkPolicies(tx, record.StaffID, record.SoftwareID); err != nil {
+		return nil, err
+	}
+	// A future-dated record waits as pending and takes no seat, budget or key until
+	// ActivateAssignment runs on its start date
+	if record.StartsAt != nil && record.StartsAt.After(time.Now()) {
+		record.Status = AssignmentPending
+		return nil, tx.Create(record).Error
+	}
+	record.Status = AssignmentActive
 
-	// Assign helper
-	assignIfNotExists := func(softwareID uint, source string) {
-		if !existing[softwareID] {
-			config.DB.Create(&models.AssignedSoftware{
-				StaffID:    staffID,
-				SoftwareID: softwareID,
-				AssignedAt: now,
-				Source:     source,
-			})
-			logAssignmentChange(staffID, softwareID, "Assigned")
-		}
+	if err := claimSeat(tx, record.SoftwareID); err != nil {
+		return nil, err
+	}
+	overBudget, err := budgetOverrun(tx, record)
+	if err != nil {
+		return nil, err
 	}
+	if err := tx.Create(record).Error; err != nil {
+		return nil, err
+	}
+	return overBudget, allocateLicenseKey(tx, record)
+}
 
-	// Department matches
-	var deptMatches []models.SoftwareDepartmentMatch
-	if err := config.DB.Where("department_id = ?", departmentID).Find(&deptMatches).Error; err != nil {
+// RevokeAssignment deletes an assignment with all its grants, returns its license key to the pool
+// and logs the action.
+func RevokeAssignment(assignment models.AssignedSoftware, action string) error {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeAssignment(tx, assignment)
+	})
+	if err != nil {
 		return err
 	}
-	for _, match := range deptMatches {
-		assignIfNotExists(match.SoftwareID, SourceDepartment)
-	}
+	logRevoked(assignment, action)
+	return nil
+}
 
-	// Team matches
-	var teamMatches []models.SoftwareTeamMatch
-	if err := config.DB.Where("team_id = ?", teamID).Find(&teamMatches).Error; err != nil {
+// revokeAssignment does the writes of RevokeAssignment inside the caller's transaction. The caller
+// logs the action with logRevoked once committed.
+func revokeAssignment(tx *gorm.DB, assignment models.AssignedSoftware) error {
+	if err := tx.Where("assigned_software_id = ?", assignment.ID).Delete(&models.AssignmentGrant{}).Error; err != nil {
 		return err
 	}
-	for _, match := range teamMatches {
-		assignIfNotExists(match.SoftwareID, SourceTeam)
+	if err := tx.Delete(&assignment).Error; err != nil {
+		return err
 	}
+	return releaseLicenseKey(tx, assignment.ID)
+}
 
-	// Org matches
-	var orgMatches []models.SoftwareOrganizationMatch
-	if err := config.DB.Find(&orgMatches).Error; err != nil {
-		return err
+// AutoAssignSoftwareToStaff assigns software based on department, team, and org matches and
+// assignment rules, at the highest plan the staff member is entitled to, and records every grant.
+// Staff who have not started yet get pending assignments that activate on their start date.
+func AutoAssignSoftwareToStaff(staffID, departmentID, teamID uint) error {
+	_, err := autoAssignSoftware(staffID, departmentID, teamID)
+	return err
+}
+
+// assignOutcome is what auto-assignment did about one software a staff member is entitled to:
+// Err is nil when the software was assigned or already held, otherwise it says why it was not
+type assignOutcome struct {
+	SoftwareID uint
+	Err        error
+}
+
+// autoAssignSoftware does the work of AutoAssignSoftwareToStaff and reports the outcome per software
+func autoAssignSoftware(staffID, departmentID, teamID uint) ([]assignOutcome, error) {
+	now := time.Now()
+
+	var staff models.StaffPlain
+	config.DB.Select("id", "start_date").First(&staff, staffID)
+	startsAt := futureStart(staff.StartDate, now)
+
+	entitlements, tiers, err := resolveEntitlements(staffID, departmentID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	orderEntitlements(entitlements)
+
+	// Fetch already assigned software
+	var assignments []models.AssignedSoftware
+	if err := config.DB.Where("staff_id = ?", staffID).Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[uint]models.AssignedSoftware)
+	for _, assignment := range assignments {
+		existing[assignment.SoftwareID] = assignment
 	}
-	for _, match := range orgMatches {
-		assignIfNotExists(match.SoftwareID, SourceOrganization)
+
+	outcomes := make([]assignOutcome, 0, len(entitlements))
+	for _, e := range entitlements {
+		if assignment, ok := existing[e.SoftwareID]; ok {
+			if err := upgradePlan(assignment, e.PlanID, tiers); err != nil {
+				log.Printf("Plan upgrade of software %d for staff %d failed: %v", e.SoftwareID, staffID, err)
+			}
+			for _, grant := range e.Grants {
+				if err := AddGrant(assignment.ID, grant); err != nil {
+					log.Printf("Failed to record grant of software %d for staff %d: %v", e.SoftwareID, staffID, err)
+				}
+			}
+			outcomes = append(outcomes, assignOutcome{SoftwareID: e.SoftwareID})
+			continue
+		}
+
+		record := models.AssignedSoftware{
+			StaffID:    staffID,
+			SoftwareID: e.SoftwareID,
+			PlanID:     e.PlanID,
+			AssignedAt: now,
+			StartsAt:   startsAt,
+			Source:     e.Source,
+			Grants:     e.Grants,
+		}
+		if err := CreateAssignment(&record); err != nil {
+			log.Printf("Auto-assignment of software %d to staff %d skipped: %v", e.SoftwareID, staffID, err)
+			outcomes = append(outcomes, assignOutcome{SoftwareID: e.SoftwareID, Err: err})
+			continue
+		}
+		logAssigned(record)
+		outcomes = append(outcomes, assignOutcome{SoftwareID: e.SoftwareID})
 	}
 
-	return nil
+	return outcomes, nil
 }
 
 // SyncSoftwareAssignmentsForStaff is called when staff changes team or department
 func SyncSoftwareAssignmentsForStaff(staffID, oldDeptID, oldTeamID, newDeptID, newTeamID uint) error {
-	// Revoke old department/team software
-	revokeSoftwareFromSource(staffID, oldDeptID, SourceDepartment)
-	revokeSoftwareFromSource(staffID, oldTeamID, SourceTeam)
+	// Drop grants of the old department, team and rules; software left without a grant is revoked
+	revokeUngrantedAccess(staffID, newDeptID, newTeamID)
 
 	// Reassign for new department, team, and org
 	return AutoAssignSoftwareToStaff(staffID, newDeptID, newTeamID)
 }
 
-// RevokeSoftwareAssignmentsForStaff is called during offboarding
+// RevokeSoftwareAssignmentsForStaff is called during offboarding. It drops every match and rule
+// grant; software that was also assigned manually is kept.
 func RevokeSoftwareAssignmentsForStaff(staffID uint) error {
 	var assignments []models.AssignedSoftware
 	if err := config.DB.Where("staff_id = ?", staffID).Find(&assignments).Error; err != nil {
@@ -91,73 +204,96 @@ func RevokeSoftwareAssignmentsForStaff(staffID uint) error {
 	}
 
 	for _, assignment := range assignments {
-		if isAutoAssigned(assignment.Source) {
-			config.DB.Delete(&assignment)
-			logAssignmentChange(assignment.StaffID, assignment.SoftwareID, "Unassigned")
+		if err := removeGrants(assignment, isAutoGrant, ActionUnassigned); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// AutoAssignSoftwareToStaffByUnit assigns a single software to a list of staff
-func AutoAssignSoftwareToStaffByUnit(softwareID uint, staffList []models.Staff, source string) {
+// AutoAssignSoftwareToStaffByUnit assigns a single software (optionally at a plan) to a list of staff
+// on behalf of the match or rule identified by sourceType and sourceID. Staff who already hold the
+// software get the grant recorded, and are upgraded when they hold it on a lower plan. Staff excluded
+// from the software are skipped, and staff who have not started yet get a pending assignment.
+func AutoAssignSoftwareToStaffByUnit(softwareID uint, planID *uint, staffList []models.Staff, sourceType string, sourceID uint) {
+	grantToStaff(softwareID, staffList, models.AssignmentGrant{SourceType: sourceType, SourceID: sourceID, PlanID: planID})
+}
+
+// grantToStaff gives a software to a list of staff on behalf of a single grant, see AutoAssignSoftwareToStaffByUnit
+func grantToStaff(softwareID uint, staffList []models.Staff, grant models.AssignmentGrant) {
 	now := time.Now()
+	planID := grant.PlanID
+	tiers, err := planTiers()
+	if err != nil {
+		log.Println("Failed to load plan tiers:", err)
+		return
+	}
+	excluded, err := activeExclusions(softwareID)
+	if err != nil {
+		log.Println("Failed to load software exclusions:", err)
+		return
+	}
+
 	for _, staff := range staffList {
+		if excluded[staff.ID][softwareID] {
+			continue
+		}
+
 		var existing models.AssignedSoftware
 		if err := config.DB.
 			Where("staff_id = ? AND software_id = ?", staff.ID, softwareID).
-			First(&existing).Error; err != nil {
-			config.DB.Create(&models.AssignedSoftware{
+			First(&existing).Error; err == nil {
+			if err := upgradePlan(existing, planID, tiers); err != nil {
+				log.Printf("Plan upgrade of software %d for staff %d failed: %v", softwareID, staff.ID, err)
+			}
+			if err := AddGrant(existing.ID, grant); err != nil {
+				log.Printf("Failed to record grant of software %d for staff %d: %v", softwareID, staff.ID, err)
+			}
+		} else {
+			record := models.AssignedSoftware{
 				StaffID:    staff.ID,
 				SoftwareID: softwareID,
-				Source:     source,
+				PlanID:     planID,
+				Source:     assignmentSource(grant),
 				AssignedAt: now,
-			})
-			logAssignmentChange(staff.ID, softwareID, "Assigned")
+				StartsAt:   futureStart(staff.StartDate, now),
+				Grants:     []models.AssignmentGrant{grant},
+			}
+			if err := CreateAssignment(&record); err != nil {
+				log.Printf("Auto-assignment of software %d to staff %d skipped: %v", softwareID, staff.ID, err)
+				continue
+			}
+			logAssigned(record)
 		}
 	}
 }
 
-// AutoRevokeSoftwareFromStaff revokes software from a list of staff (only if auto-assigned)
-func AutoRevokeSoftwareFromStaff(softwareID uint, staffList []models.Staff, source string) {
+// AutoRevokeSoftwareFromStaff removes the grant of the match identified by sourceType and sourceID
+// from a list of staff. Software is only revoked from staff left without any other grant for it.
+func AutoRevokeSoftwareFromStaff(softwareID uint, staffList []models.Staff, sourceType string, sourceID uint) {
 	for _, staff := range staffList {
-		result := config.DB.Where("staff_id = ? AND software_id = ? AND source = ?", staff.ID, softwareID, source).
-			Delete(&models.AssignedSoftware{})
-
-		if result.RowsAffected > 0 {
-			logAssignmentChange(staff.ID, softwareID, "Unassigned")
-		}
+		revokeGrant(staff.ID, softwareID, sourceType, sourceID, ActionUnassigned)
 	}
 }
 
-// revokeSoftwareFromSource is used internally for department/team match removal
-func revokeSoftwareFromSource(staffID, id uint, source string) {
-	var softwareIDs []uint
+// isAutoAssigned checks if the assignment source is one of the match- or rule-based sources
+func isAutoAssigned(source string) bool {
+	return source == SourceDepartment || source == SourceTeam || source == SourceOrganization || source == SourceRule
+}
 
-	switch source {
-	case SourceDepartment:
-		config.DB.Model(&models.SoftwareDepartmentMatch{}).
-			Where("department_id = ?", id).
-			Pluck("software_id", &softwareIDs)
-	case SourceTeam:
-		config.DB.Model(&models.SoftwareTeamMatch{}).
-			Where("team_id = ?", id).
-			Pluck("software_id", &softwareIDs)
-	}
-
-	for _, sid := range softwareIDs {
-		result := config.DB.Where("staff_id = ? AND software_id = ? AND source = ?", staffID, sid, source).
-			Delete(&models.AssignedSoftware{})
-		if result.RowsAffected > 0 {
-			logAssignmentChange(staffID, sid, "Unassigned")
-		}
+// logAssigned logs a new assignment unless it is pending; pending assignments are logged on activation
+func logAssigned(record models.AssignedSoftware) {
+	if record.Status != AssignmentPending {
+		logAssignmentChange(record.StaffID, record.SoftwareID, ActionAssigned)
 	}
 }
 
-// isAutoAssigned checks if the assignment source is one of the match-based sources
-func isAutoAssigned(source string) bool {
-	return source == SourceDepartment || source == SourceTeam || source == SourceOrganization
+// logRevoked logs a revocation unless the assignment was pending, as it was never logged as assigned
+func logRevoked(assignment models.AssignedSoftware, action string) {
+	if assignment.Status != AssignmentPending {
+		logAssignmentChange(assignment.StaffID, assignment.SoftwareID, action)
+	}
 }
 
 // logAssignmentChange writes an assignment or unassignment log
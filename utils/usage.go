@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"software_management/config"
+	"software_management/models"
+)
+
+// Usage import formats
+const (
+	UsageFormatJSONL = "jsonl"
+	UsageFormatCSV   = "csv"
+)
+
+// maxUsageImportErrors caps how many line errors are echoed back from an import
+const maxUsageImportErrors = 100
+
+// usageTimeLayouts are the timestamp layouts accepted in usage imports
+var usageTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseUsageRecords reads usage records from a JSONL or CSV stream.
+// CSV input needs a header row; columns are matched by name.
+func ParseUsageRecords(r io.Reader, format string) ([]models.UsageRecord, []models.UsageImportError, error) {
+	switch format {
+	case UsageFormatJSONL:
+		return parseUsageJSONL(r)
+	case UsageFormatCSV:
+		return parseUsageCSV(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported usage format %q, expected jsonl or csv", format)
+	}
+}
+
+func parseUsageJSONL(r io.Reader) ([]models.UsageRecord, []models.UsageImportError, error) {
+	var records []models.UsageRecord
+	var lineErrors []models.UsageImportError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var record models.UsageRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			lineErrors = append(lineErrors, models.UsageImportError{Line: line, Error: "invalid JSON: " + err.Error()})
+			continue
+		}
+		record.Line = line
+		records = append(records, record)
+	}
+	return records, lineErrors, scanner.Err()
+}
+
+func parseUsageCSV(r io.Reader) ([]models.UsageRecord, []models.UsageImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing CSV header: %w", err)
+	}
+
+	// Map known column names (and a few common aliases) to their position
+	columns := make(map[string]int)
+	aliases := map[string]string{
+		"email": "staff_email", "software_name": "software", "event": "event_type",
+		"occurred_at": "timestamp", "last_seen": "timestamp", "time": "timestamp",
+	}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["timestamp"]; !ok {
+		return nil, nil, errors.New("CSV header must include a timestamp column")
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []models.UsageRecord
+	var lineErrors []models.UsageImportError
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			lineErrors = append(lineErrors, models.UsageImportError{Line: line, Error: err.Error()})
+			continue
+		}
+
+		record := models.UsageRecord{
+			Line:       line,
+			StaffEmail: field(row, "staff_email"),
+			Software:   field(row, "software"),
+			Timestamp:  field(row, "timestamp"),
+			EventType:  field(row, "event_type"),
+		}
+		if id, err := strconv.Atoi(field(row, "staff_id")); err == nil {
+			record.StaffID = uint(id)
+		}
+		if id, err := strconv.Atoi(field(row, "software_id")); err == nil {
+			record.SoftwareID = uint(id)
+		}
+		records = append(records, record)
+	}
+	return records, lineErrors, nil
+}
+
+// ParseUsageTime parses a usage timestamp in one of the accepted layouts, or as Unix seconds
+func ParseUsageTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range usageTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// ImportUsageRecords stores usage events and moves each matching assignment's last_used_at forward
+func ImportUsageRecords(records []models.UsageRecord, lineErrors []models.UsageImportError) models.UsageImportResult {
+	result := models.UsageImportResult{Received: len(records) + len(lineErrors)}
+	addError := func(line int, msg string) {
+		result.Rejected++
+		if len(result.Errors) < maxUsageImportErrors {
+			result.Errors = append(result.Errors, models.UsageImportError{Line: line, Error: msg})
+		}
+	}
+	for _, e := range lineErrors {
+		addError(e.Line, e.Error)
+	}
+
+	staffByEmail := make(map[string]uint)
+	softwareByName := make(map[string]uint)
+
+	for _, record := range records {
+		staffID, err := resolveUsageStaff(record, staffByEmail)
+		if err != nil {
+			addError(record.Line, err.Error())
+			continue
+		}
+		softwareID, err := resolveUsageSoftware(record, softwareByName)
+		if err != nil {
+			addError(record.Line, err.Error())
+			continue
+		}
+		occurredAt, err := ParseUsageTime(record.Timestamp)
+		if err != nil {
+			addError(record.Line, err.Error())
+			continue
+		}
+
+		eventType := record.EventType
+		if eventType == "" {
+			eventType = "active"
+		}
+		event := models.UsageEvent{
+			StaffID:    staffID,
+			SoftwareID: softwareID,
+			EventType:  eventType,
+			OccurredAt: occurredAt,
+		}
+
+		var assignment models.AssignedSoftware
+		if err := config.DB.Where("staff_id = ? AND software_id = ?", staffID, softwareID).First(&assignment).Error; err == nil {
+			event.AssignedSoftwareID = &assignment.ID
+		}
+
+		if err := config.DB.Create(&event).Error; err != nil {
+			addError(record.Line, err.Error())
+			continue
+		}
+		result.Imported++
+
+		if event.AssignedSoftwareID != nil {
+			result.MatchedAssignments++
+			config.DB.Model(&models.AssignedSoftware{}).
+				Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", assignment.ID, occurredAt).
+				Update("last_used_at", occurredAt)
+		}
+	}
+
+	if result.Errors == nil {
+		result.Errors = []models.UsageImportError{}
+	}
+	return result
+}
+
+// resolveUsageStaff finds the staff ID of a usage record by ID or email
+func resolveUsageStaff(record models.UsageRecord, cache map[string]uint) (uint, error) {
+	if record.StaffID != 0 {
+		var count int64
+		config.DB.Model(&models.StaffPlain{}).Where("id = ?", record.StaffID).Count(&count)
+		if count == 0 {
+			return 0, fmt.Errorf("unknown staff ID %d", record.StaffID)
+		}
+		return record.StaffID, nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(record.StaffEmail))
+	if email == "" {
+		return 0, errors.New("staff_email or staff_id is required")
+	}
+	if id, ok := cache[email]; ok {
+		return id, nil
+	}
+	var staff models.StaffPlain
+	if err := config.DB.Where("LOWER(email) = ?", email).First(&staff).Error; err != nil {
+		return 0, fmt.Errorf("unknown staff email %s", email)
+	}
+	cache[email] = staff.ID
+	return staff.ID, nil
+}
+
+// resolveUsageSoftware finds the software ID of a usage record by ID or name
+func resolveUsageSoftware(record models.UsageRecord, cache map[string]uint) (uint, error) {
+	if record.SoftwareID != 0 {
+		var count int64
+		config.DB.Model(&models.Software{}).Where("id = ?", record.SoftwareID).Count(&count)
+		if count == 0 {
+			return 0, fmt.Errorf("unknown software ID %d", record.SoftwareID)
+		}
+		return record.SoftwareID, nil
+	}
+
+	name := strings.TrimSpace(record.Software)
+	if name == "" {
+		return 0, errors.New("software or software_id is required")
+	}
+	if id, ok := cache[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	var software models.Software
+	if err := config.DB.Where("LOWER(name) = ?", strings.ToLower(name)).First(&software).Error; err != nil {
+		return 0, fmt.Errorf("unknown software %s", name)
+	}
+	cache[strings.ToLower(name)] = software.ID
+	return software.ID, nil
+}
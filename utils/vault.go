@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrVaultKeyMissing is returned when LICENSE_VAULT_KEY is not configured
+var ErrVaultKeyMissing = errors.New("license vault key is not configured (set LICENSE_VAULT_KEY)")
+
+// vaultKey reads the AES-256 key from LICENSE_VAULT_KEY (base64-encoded, 32 bytes)
+func vaultKey() ([]byte, error) {
+	encoded := os.Getenv("LICENSE_VAULT_KEY")
+	if encoded == "" {
+		return nil, ErrVaultKeyMissing
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("LICENSE_VAULT_KEY must be a base64-encoded 32-byte key")
+	}
+	return key, nil
+}
+
+// EncryptSecret encrypts plaintext with AES-256-GCM and returns base64(nonce || ciphertext)
+func EncryptSecret(plaintext string) (string, error) {
+	gcm, err := vaultCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret reverses EncryptSecret
+func DecryptSecret(encrypted string) (string, error) {
+	gcm, err := vaultCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// vaultCipher builds the AES-GCM cipher from the configured vault key
+func vaultCipher() (cipher.AEAD, error) {
+	key, err := vaultKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}